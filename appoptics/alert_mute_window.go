@@ -0,0 +1,175 @@
+package appoptics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	alertMuteWindowTimeLayout     = "15:04"
+	alertMuteWindowDateTimeLayout = "2006-01-02T15:04"
+)
+
+var alertMuteWindowWeekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// A mute window is either recurring, on days between start and end, or one-off,
+// between starts_at and ends_at. Times are local to time_zone.
+func resourceAppOpticsAlertMuteWindowResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"time_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "UTC",
+				ValidateFunc: validateTimeZone,
+			},
+			"days": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(alertMuteWindowWeekdays, false),
+				},
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`), "expected a time of the form HH:MM"),
+			},
+			"end": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`), "expected a time of the form HH:MM"),
+			},
+			"starts_at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAlertMuteWindowDateTime,
+			},
+			"ends_at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAlertMuteWindowDateTime,
+			},
+		},
+	}
+}
+
+func validateTimeZone(v interface{}, k string) ([]string, []error) {
+	if _, err := time.LoadLocation(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q isn't a known time zone: %s", k, err)}
+	}
+	return nil, nil
+}
+
+func validateAlertMuteWindowDateTime(v interface{}, k string) ([]string, []error) {
+	if _, err := time.Parse(alertMuteWindowDateTimeLayout, v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q should be of the form YYYY-MM-DDTHH:MM: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// Returns whether now falls into any of the mute windows
+func alertMutedAt(windows []interface{}, now time.Time) (bool, error) {
+	for i, w := range windows {
+		window, ok := w.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		muted, err := alertMuteWindowContains(window, now)
+		if err != nil {
+			return false, fmt.Errorf("mute_window.%d: %s", i, err)
+		}
+		if muted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func alertMuteWindowContains(window map[string]interface{}, now time.Time) (bool, error) {
+	timeZone, _ := window["time_zone"].(string)
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return false, err
+	}
+	now = now.In(loc)
+
+	days, _ := window["days"].([]interface{})
+	start, _ := window["start"].(string)
+	end, _ := window["end"].(string)
+	startsAt, _ := window["starts_at"].(string)
+	endsAt, _ := window["ends_at"].(string)
+
+	recurring := len(days) > 0 || start != "" || end != ""
+	oneOff := startsAt != "" || endsAt != ""
+	switch {
+	case recurring && oneOff:
+		return false, fmt.Errorf("a window is either recurring (days, start, end) or one-off (starts_at, ends_at)")
+	case oneOff:
+		if startsAt == "" || endsAt == "" {
+			return false, fmt.Errorf("one-off windows need both starts_at and ends_at")
+		}
+		from, err := time.ParseInLocation(alertMuteWindowDateTimeLayout, startsAt, loc)
+		if err != nil {
+			return false, err
+		}
+		to, err := time.ParseInLocation(alertMuteWindowDateTimeLayout, endsAt, loc)
+		if err != nil {
+			return false, err
+		}
+		if !to.After(from) {
+			return false, fmt.Errorf("ends_at must be after starts_at")
+		}
+		return !now.Before(from) && now.Before(to), nil
+	case recurring:
+		if len(days) == 0 || start == "" || end == "" {
+			return false, fmt.Errorf("recurring windows need days, start and end")
+		}
+		from, err := time.Parse(alertMuteWindowTimeLayout, start)
+		if err != nil {
+			return false, err
+		}
+		to, err := time.Parse(alertMuteWindowTimeLayout, end)
+		if err != nil {
+			return false, err
+		}
+
+		// A window ending before it starts runs past midnight, so the one
+		// that started yesterday may still be going on
+		for _, offset := range []int{0, -1} {
+			day := now.AddDate(0, 0, offset)
+			if !alertMuteWindowOnDay(days, day.Weekday()) {
+				continue
+			}
+			windowStart := time.Date(day.Year(), day.Month(), day.Day(), from.Hour(), from.Minute(), 0, 0, loc)
+			windowEnd := time.Date(day.Year(), day.Month(), day.Day(), to.Hour(), to.Minute(), 0, 0, loc)
+			if !windowEnd.After(windowStart) {
+				windowEnd = windowEnd.AddDate(0, 0, 1)
+			}
+			if !now.Before(windowStart) && now.Before(windowEnd) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("a window needs either days, start and end or starts_at and ends_at")
+	}
+}
+
+func alertMuteWindowOnDay(days []interface{}, weekday time.Weekday) bool {
+	for _, day := range days {
+		if strings.EqualFold(day.(string), weekday.String()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package appoptics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertMutedAt(t *testing.T) {
+	nightly := map[string]interface{}{
+		"time_zone": "Europe/Berlin",
+		"days":      []interface{}{"saturday"},
+		"start":     "22:00",
+		"end":       "02:00",
+	}
+	release := map[string]interface{}{
+		"time_zone": "America/New_York",
+		"starts_at": "2026-11-03T09:00",
+		"ends_at":   "2026-11-03T11:30",
+	}
+	windows := []interface{}{nightly, release}
+
+	cases := []struct {
+		now   string
+		muted bool
+	}{
+		// Saturday 2026-11-07, 23:30 in Berlin
+		{"2026-11-07T22:30:00Z", true},
+		// Past midnight, still in the window that started on Saturday
+		{"2026-11-08T00:30:00Z", true},
+		{"2026-11-08T01:00:00Z", false},
+		// Friday night isn't muted
+		{"2026-11-06T22:30:00Z", false},
+		// 10:00 in New York
+		{"2026-11-03T15:00:00Z", true},
+		{"2026-11-03T16:30:00Z", false},
+	}
+
+	for _, c := range cases {
+		now, err := time.Parse(time.RFC3339, c.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		muted, err := alertMutedAt(windows, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if muted != c.muted {
+			t.Errorf("at %s: expected muted %t, got %t", c.now, c.muted, muted)
+		}
+	}
+}
+
+func TestAlertMutedAtInvalidWindows(t *testing.T) {
+	invalid := []map[string]interface{}{
+		{"days": []interface{}{"monday"}, "start": "10:00", "end": "11:00", "starts_at": "2026-11-03T09:00"},
+		{"days": []interface{}{"monday"}, "start": "10:00"},
+		{"starts_at": "2026-11-03T09:00"},
+		{"starts_at": "2026-11-03T09:00", "ends_at": "2026-11-03T08:00"},
+		{},
+	}
+
+	for _, window := range invalid {
+		if _, err := alertMutedAt([]interface{}{window}, time.Now()); err == nil {
+			t.Errorf("expected an error for %#v", window)
+		}
+	}
+}
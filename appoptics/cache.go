@@ -0,0 +1,269 @@
+package appoptics
+
+import (
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/appoptics/appoptics-api-go"
+)
+
+// spaceChartCache holds the charts of each space for the duration of a run,
+// so that refreshing many appoptics_dashboard_chart resources of one space
+// lists its charts once instead of retrieving them one by one. It also indexes
+// the charts by their related space for charts_linking_here.
+type spaceChartCache struct {
+	mu     sync.Mutex
+	spaces map[int]*spaceChartCacheEntry
+
+	indexMu    sync.Mutex
+	links      map[int][]spaceChartLink // nil until indexed
+	generation int                      // bumped by every invalidation
+}
+
+// spaceChartLink is a chart whose related_space is another space
+type spaceChartLink struct {
+	spaceID   int
+	chartID   int
+	chartName string
+}
+
+// spaceChartCacheEntry is the chart listing of a single space
+type spaceChartCacheEntry struct {
+	mu     sync.Mutex
+	loaded bool
+	charts map[int]*spaceChartData
+}
+
+func newSpaceChartCache() *spaceChartCache {
+	return &spaceChartCache{
+		spaces: make(map[int]*spaceChartCacheEntry),
+	}
+}
+
+// Returns a chart from the listing of its space, which is fetched on first
+// use. Charts missing from the listing are retrieved on their own.
+func (c *spaceChartCache) retrieve(client *appoptics.Client, chartID, spaceID int) (*spaceChartData, error) {
+	entry, err := c.load(client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.mu.Lock()
+	chart, ok := entry.charts[chartID]
+	entry.mu.Unlock()
+
+	if !ok {
+		return spaceChartRetrieve(client, chartID, spaceID)
+	}
+	cached := *chart
+	return &cached, nil
+}
+
+// Returns copies of the charts of a space, in no particular order
+func (c *spaceChartCache) list(client *appoptics.Client, spaceID int) ([]*spaceChartData, error) {
+	entry, err := c.load(client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	charts := make([]*spaceChartData, 0, len(entry.charts))
+	for _, chart := range entry.charts {
+		cached := *chart
+		charts = append(charts, &cached)
+	}
+	return charts, nil
+}
+
+// Returns the entry of a space, listing its charts if they aren't yet
+func (c *spaceChartCache) load(client *appoptics.Client, spaceID int) (*spaceChartCacheEntry, error) {
+	c.mu.Lock()
+	entry, ok := c.spaces[spaceID]
+	if !ok {
+		entry = &spaceChartCacheEntry{}
+		c.spaces[spaceID] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.loaded {
+		charts, err := spaceChartList(client, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[DEBUG] Cached %d charts of AppOptics space %d", len(charts), spaceID)
+		entry.charts = make(map[int]*spaceChartData, len(charts))
+		for _, chart := range charts {
+			entry.charts[chart.ID] = chart
+		}
+		entry.loaded = true
+	}
+	return entry, nil
+}
+
+// Returns the charts whose related_space is spaceID, by space and chart ID.
+// The index is built from the listing of every space once per run, and again
+// after charts were written to, which only lists the spaces written to anew.
+func (c *spaceChartCache) linking(client *appoptics.Client, spaceID int) ([]spaceChartLink, error) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+
+	c.mu.Lock()
+	links, generation := c.links, c.generation
+	c.mu.Unlock()
+
+	if links == nil {
+		spaces, err := spaceList(client)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(spaces, func(i, j int) bool { return spaces[i].ID < spaces[j].ID })
+
+		links = make(map[int][]spaceChartLink)
+		for _, space := range spaces {
+			charts, err := c.list(client, space.ID)
+			if err != nil {
+				return nil, err
+			}
+			sort.Slice(charts, func(i, j int) bool { return charts[i].ID < charts[j].ID })
+			for _, chart := range charts {
+				if chart.RelatedSpace != 0 {
+					links[chart.RelatedSpace] = append(links[chart.RelatedSpace], spaceChartLink{
+						spaceID:   space.ID,
+						chartID:   chart.ID,
+						chartName: chart.Name,
+					})
+				}
+			}
+		}
+		log.Printf("[DEBUG] Indexed the related spaces of the charts of %d AppOptics spaces", len(spaces))
+
+		// A write while indexing leaves the index to the next caller
+		c.mu.Lock()
+		if c.generation == generation {
+			c.links = links
+		}
+		c.mu.Unlock()
+	}
+	return links[spaceID], nil
+}
+
+// Drops the charts of a space, to be called after anything wrote to them or
+// the space was created or deleted
+func (c *spaceChartCache) invalidate(spaceID int) {
+	c.mu.Lock()
+	delete(c.spaces, spaceID)
+	c.links = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// spaceExistenceCache remembers the spaces found to exist during a run, so that
+// the related_space of many charts linking to the same space is checked once.
+// Missing spaces aren't remembered, they may still be created by the run.
+type spaceExistenceCache struct {
+	mu     sync.Mutex
+	exists map[int]bool
+}
+
+func newSpaceExistenceCache() *spaceExistenceCache {
+	return &spaceExistenceCache{
+		exists: make(map[int]bool),
+	}
+}
+
+// Reports whether a space exists, retrieving it unless it was found before
+func (c *spaceExistenceCache) check(client *appoptics.Client, spaceID int) (bool, error) {
+	c.mu.Lock()
+	found := c.exists[spaceID]
+	c.mu.Unlock()
+	if found {
+		return true, nil
+	}
+
+	if _, err := client.SpacesService().Retrieve(spaceID); err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return false, nil
+		}
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.exists[spaceID] = true
+	c.mu.Unlock()
+	return true, nil
+}
+
+// Drops a space, to be called after deleting it
+func (c *spaceExistenceCache) invalidate(spaceID int) {
+	c.mu.Lock()
+	delete(c.exists, spaceID)
+	c.mu.Unlock()
+}
+
+// metricCache holds every metric of the account once it has been listed, so
+// that refreshing many appoptics_metric resources doesn't retrieve them one by
+// one. It's only used when the provider's prefetch_metrics setting is on.
+type metricCache struct {
+	once    sync.Once
+	mu      sync.Mutex
+	metrics map[string]*appoptics.Metric
+}
+
+func newMetricCache() *metricCache {
+	return &metricCache{}
+}
+
+// Returns a metric from the listing of all metrics, which is fetched on first
+// use. Metrics missing from the listing, or written to since, are retrieved on
+// their own.
+func (c *metricCache) retrieve(client *appoptics.Client, name string) (*appoptics.Metric, error) {
+	c.once.Do(func() {
+		metrics, err := metricList(client)
+		if err != nil {
+			log.Printf("[WARN] Couldn't prefetch AppOptics metrics, reading them one by one: %s", err)
+			return
+		}
+		log.Printf("[DEBUG] Cached %d AppOptics metrics", len(metrics))
+		c.mu.Lock()
+		c.metrics = metrics
+		c.mu.Unlock()
+	})
+
+	c.mu.Lock()
+	metric, ok := c.metrics[name]
+	c.mu.Unlock()
+
+	if !ok {
+		return client.MetricsService().Retrieve(name)
+	}
+	cached := *metric
+	return &cached, nil
+}
+
+// Drops a metric, to be called after anything wrote to it
+func (c *metricCache) invalidate(name string) {
+	c.mu.Lock()
+	delete(c.metrics, name)
+	c.mu.Unlock()
+}
+
+// Returns every metric of the account, following the pagination of the API
+func metricList(client *appoptics.Client) (map[string]*appoptics.Metric, error) {
+	metrics := make(map[string]*appoptics.Metric)
+	err := listPages(client, "metrics", func() interface{} { return &appoptics.MetricsResponse{} }, func(page interface{}) (int, int) {
+		p := page.(*appoptics.MetricsResponse)
+		for i := range p.Metrics {
+			metrics[p.Metrics[i].Name] = &p.Metrics[i]
+		}
+		return len(p.Metrics), p.Query.Found
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
@@ -0,0 +1,119 @@
+package appoptics
+
+import (
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+)
+
+func TestSpaceChartCache(t *testing.T) {
+	cache := newSpaceChartCache()
+	cache.spaces[1] = &spaceChartCacheEntry{
+		loaded: true,
+		charts: map[int]*spaceChartData{
+			10: {Chart: appoptics.Chart{ID: 10, Name: "CPU"}},
+		},
+	}
+
+	// A loaded space is served without touching the API
+	chart, err := cache.retrieve(nil, 10, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if chart.Name != "CPU" {
+		t.Errorf("unexpected chart: %#v", chart)
+	}
+
+	chart.Name = "Memory"
+	if cache.spaces[1].charts[10].Name != "CPU" {
+		t.Error("retrieved charts should be copies of the cached ones")
+	}
+
+	cache.invalidate(1)
+	if _, ok := cache.spaces[1]; ok {
+		t.Error("expected the space to be dropped from the cache")
+	}
+}
+
+func TestMetricCache(t *testing.T) {
+	cache := newMetricCache()
+	cache.once.Do(func() {
+		cache.metrics = map[string]*appoptics.Metric{
+			"system.cpu.utilization": {Name: "system.cpu.utilization", Type: "gauge"},
+		}
+	})
+
+	metric, err := cache.retrieve(nil, "system.cpu.utilization")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if metric.Type != "gauge" {
+		t.Errorf("unexpected metric: %#v", metric)
+	}
+
+	cache.invalidate("system.cpu.utilization")
+	if _, ok := cache.metrics["system.cpu.utilization"]; ok {
+		t.Error("expected the metric to be dropped from the cache")
+	}
+}
+
+func TestSpaceChartCacheList(t *testing.T) {
+	cache := newSpaceChartCache()
+	cache.spaces[1] = &spaceChartCacheEntry{
+		loaded: true,
+		charts: map[int]*spaceChartData{
+			10: {Chart: appoptics.Chart{ID: 10, Name: "CPU", RelatedSpace: 2}},
+			11: {Chart: appoptics.Chart{ID: 11, Name: "Memory"}},
+		},
+	}
+
+	charts, err := cache.list(nil, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(charts) != 2 {
+		t.Fatalf("expected 2 charts, got %d", len(charts))
+	}
+
+	charts[0].Name = "Disk"
+	if cache.spaces[1].charts[10].Name != "CPU" || cache.spaces[1].charts[11].Name != "Memory" {
+		t.Error("listed charts should be copies of the cached ones")
+	}
+}
+
+func TestSpaceExistenceCache(t *testing.T) {
+	cache := newSpaceExistenceCache()
+	cache.exists[2] = true
+
+	// A space found before isn't retrieved again
+	exists, err := cache.check(nil, 2)
+	if err != nil || !exists {
+		t.Errorf("expected space 2 to exist, got %t, %v", exists, err)
+	}
+
+	cache.invalidate(2)
+	if cache.exists[2] {
+		t.Error("expected the space to be dropped from the cache")
+	}
+}
+
+func TestSpaceChartCacheLinking(t *testing.T) {
+	cache := newSpaceChartCache()
+	cache.links = map[int][]spaceChartLink{
+		2: {{spaceID: 1, chartID: 10, chartName: "Drill down"}},
+	}
+
+	// An indexed run is served without touching the API
+	links, err := cache.linking(nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(links) != 1 || links[0].chartID != 10 {
+		t.Errorf("unexpected links: %#v", links)
+	}
+
+	cache.invalidate(1)
+	if cache.links != nil {
+		t.Error("expected a write to drop the index")
+	}
+}
@@ -0,0 +1,150 @@
+package appoptics
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceAppOpticsAlertStatus() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAppOpticsAlertStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"alert_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"triggered": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"triggered_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"firing": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"triggered_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// alertStatusResponse is the status of a single alert
+type alertStatusResponse struct {
+	Alert struct {
+		ID int `json:"id"`
+	} `json:"alert"`
+	Status string `json:"status"`
+}
+
+// alertStatusListResponse is the status of all alerts that fired recently.
+// Alerts grouped by tags have one entry per firing tag set.
+type alertStatusListResponse struct {
+	Firing  []alertStatusEntry `json:"firing"`
+	Cleared []alertStatusEntry `json:"cleared"`
+}
+
+type alertStatusEntry struct {
+	ID          int               `json:"id"`
+	TriggeredAt int64             `json:"triggered_at,omitempty"`
+	ClearedAt   int64             `json:"cleared_at,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func dataSourceAppOpticsAlertStatusRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	alertID := d.Get("alert_id").(int)
+
+	req, err := client.NewRequest("GET", fmt.Sprintf("alerts/%d/status", alertID), nil)
+	if err != nil {
+		return err
+	}
+	status := &alertStatusResponse{}
+	if _, err := client.Do(req, status); err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return fmt.Errorf("AppOptics alert %d doesn't exist", alertID)
+		}
+		return fmt.Errorf("Error reading status of AppOptics alert %d: %s", alertID, err)
+	}
+
+	req, err = client.NewRequest("GET", "alerts/status", nil)
+	if err != nil {
+		return err
+	}
+	statuses := &alertStatusListResponse{}
+	if _, err := client.Do(req, statuses); err != nil {
+		return fmt.Errorf("Error reading status of AppOptics alerts: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(alertID))
+	if err := d.Set("status", status.Status); err != nil {
+		return err
+	}
+
+	triggeredAt, firing := flattenAlertStatusFiring(alertID, statuses.Firing)
+	if err := d.Set("triggered", status.Status == "triggered"); err != nil {
+		return err
+	}
+	if err := d.Set("triggered_at", triggeredAt); err != nil {
+		return err
+	}
+	return d.Set("firing", firing)
+}
+
+// Returns the firing tag sets of the alert and when the earliest of them was
+// triggered, as RFC 3339
+func flattenAlertStatusFiring(alertID int, entries []alertStatusEntry) (string, []interface{}) {
+	var earliest int64
+	firing := make([]interface{}, 0)
+	for _, entry := range entries {
+		if entry.ID != alertID {
+			continue
+		}
+		if earliest == 0 || (entry.TriggeredAt != 0 && entry.TriggeredAt < earliest) {
+			earliest = entry.TriggeredAt
+		}
+
+		tags := make(map[string]interface{}, len(entry.Tags))
+		for k, v := range entry.Tags {
+			tags[k] = v
+		}
+		firing = append(firing, map[string]interface{}{
+			"tags":         tags,
+			"triggered_at": formatAlertStatusTime(entry.TriggeredAt),
+		})
+	}
+
+	return formatAlertStatusTime(earliest), firing
+}
+
+func formatAlertStatusTime(epoch int64) string {
+	if epoch == 0 {
+		return ""
+	}
+	return time.Unix(epoch, 0).UTC().Format(time.RFC3339)
+}
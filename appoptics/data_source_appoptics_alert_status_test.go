@@ -0,0 +1,72 @@
+package appoptics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccAppOpticsAlertStatusDataSource(t *testing.T) {
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsAlertStatusDataSourceConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.appoptics_alert_status.foobar", "alert_id", "appoptics_alert.foobar", "id"),
+					resource.TestCheckResourceAttr(
+						"data.appoptics_alert_status.foobar", "triggered", "false"),
+					resource.TestCheckResourceAttr(
+						"data.appoptics_alert_status.foobar", "firing.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenAlertStatusFiring(t *testing.T) {
+	entries := []alertStatusEntry{
+		{ID: 1, TriggeredAt: 1700000600, Tags: map[string]string{"region": "us-east-1"}},
+		{ID: 2, TriggeredAt: 1700000000},
+		{ID: 1, TriggeredAt: 1700000300, Tags: map[string]string{"region": "eu-west-1"}},
+	}
+
+	triggeredAt, firing := flattenAlertStatusFiring(1, entries)
+	if triggeredAt != "2023-11-14T22:18:20Z" {
+		t.Errorf("unexpected triggered_at: %s", triggeredAt)
+	}
+	if len(firing) != 2 {
+		t.Fatalf("expected 2 firing tag sets, got %d", len(firing))
+	}
+	tags := firing[1].(map[string]interface{})["tags"].(map[string]interface{})
+	if tags["region"] != "eu-west-1" {
+		t.Errorf("unexpected tags: %#v", tags)
+	}
+
+	if triggeredAt, firing := flattenAlertStatusFiring(3, entries); triggeredAt != "" || len(firing) != 0 {
+		t.Errorf("expected no firing tag sets, got %s %#v", triggeredAt, firing)
+	}
+}
+
+func testAccCheckAppOpticsAlertStatusDataSourceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "appoptics_alert" "foobar" {
+	name = "%s"
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}
+
+data "appoptics_alert_status" "foobar" {
+	alert_id = appoptics_alert.foobar.id
+}`, name)
+}
@@ -0,0 +1,237 @@
+package appoptics
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceAppOpticsMeasurements() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAppOpticsMeasurementsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"resolution": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"duration": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"summary_function": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"mean", "sum", "min", "max", "count"}, false),
+			},
+			"percentiles": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeFloat,
+					ValidateFunc: validation.FloatBetween(0, 100),
+				},
+			},
+			"series": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"measurement": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"time": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"value": {
+										Type:     schema.TypeFloat,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"measurement_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"min": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"max": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"mean": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"percentile": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeFloat},
+			},
+		},
+	}
+}
+
+// measurementsResponse is the answer of the measurements retrieve API
+type measurementsResponse struct {
+	Name       string               `json:"name"`
+	Resolution int                  `json:"resolution"`
+	Series     []measurementsSeries `json:"series"`
+}
+
+type measurementsSeries struct {
+	Tags         map[string]string   `json:"tags"`
+	Measurements []measurementsPoint `json:"measurements"`
+}
+
+type measurementsPoint struct {
+	Time  int64   `json:"time"`
+	Value float64 `json:"value"`
+}
+
+func dataSourceAppOpticsMeasurementsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	name := d.Get("name").(string)
+
+	query := url.Values{}
+	query.Set("resolution", strconv.Itoa(d.Get("resolution").(int)))
+	query.Set("duration", strconv.Itoa(d.Get("duration").(int)))
+	if v, ok := d.GetOk("summary_function"); ok {
+		query.Set("summary_function", v.(string))
+	}
+	for k, v := range d.Get("tags").(map[string]interface{}) {
+		query.Set(fmt.Sprintf("tags[%s]", k), v.(string))
+	}
+
+	measurements, err := measurementsRetrieve(client, name, query)
+	if err != nil {
+		return fmt.Errorf("Error reading measurements of AppOptics metric %s: %s", name, err)
+	}
+
+	values := make([]float64, 0)
+	series := make([]interface{}, 0, len(measurements.Series))
+	for _, s := range measurements.Series {
+		tags := make(map[string]interface{}, len(s.Tags))
+		for k, v := range s.Tags {
+			tags[k] = v
+		}
+		points := make([]interface{}, 0, len(s.Measurements))
+		for _, p := range s.Measurements {
+			points = append(points, map[string]interface{}{
+				"time":  int(p.Time),
+				"value": p.Value,
+			})
+			values = append(values, p.Value)
+		}
+		series = append(series, map[string]interface{}{
+			"tags":        tags,
+			"measurement": points,
+		})
+	}
+
+	d.SetId(name)
+	if err := d.Set("series", series); err != nil {
+		return err
+	}
+
+	aggregates := aggregateMeasurements(values)
+	for _, k := range []string{"measurement_count", "min", "max", "mean"} {
+		if err := d.Set(k, aggregates[k]); err != nil {
+			return err
+		}
+	}
+
+	percentiles := make(map[string]interface{})
+	for _, p := range d.Get("percentiles").([]interface{}) {
+		percentiles[strconv.FormatFloat(p.(float64), 'f', -1, 64)] = measurementsPercentile(values, p.(float64))
+	}
+	return d.Set("percentile", percentiles)
+}
+
+func measurementsRetrieve(client *appoptics.Client, name string, query url.Values) (*measurementsResponse, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("measurements/%s?%s", url.PathEscape(name), query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	measurements := &measurementsResponse{}
+	if _, err := client.Do(req, measurements); err != nil {
+		return nil, err
+	}
+	return measurements, nil
+}
+
+// Returns the number, min, max and mean of the values. The ones other than
+// the number are zero without values.
+func aggregateMeasurements(values []float64) map[string]interface{} {
+	aggregates := map[string]interface{}{
+		"measurement_count": len(values),
+		"min":               0.0,
+		"max":               0.0,
+		"mean":              0.0,
+	}
+	if len(values) == 0 {
+		return aggregates
+	}
+
+	min, max, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		sum += v
+	}
+	aggregates["min"] = min
+	aggregates["max"] = max
+	aggregates["mean"] = sum / float64(len(values))
+
+	return aggregates
+}
+
+// Returns the p-th percentile of the values, interpolating linearly between
+// the closest ranks
+func measurementsPercentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
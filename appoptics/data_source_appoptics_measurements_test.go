@@ -0,0 +1,76 @@
+package appoptics
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccAppOpticsMeasurementsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsMeasurementsDataSourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.appoptics_measurements.foobar", "name", "system.cpu.utilization"),
+					resource.TestCheckResourceAttrSet(
+						"data.appoptics_measurements.foobar", "measurement_count"),
+					resource.TestCheckResourceAttrSet(
+						"data.appoptics_measurements.foobar", "percentile.95"),
+				),
+			},
+		},
+	})
+}
+
+func TestAggregateMeasurements(t *testing.T) {
+	aggregates := aggregateMeasurements([]float64{4, 1, 3, 2})
+	expected := map[string]interface{}{"measurement_count": 4, "min": 1.0, "max": 4.0, "mean": 2.5}
+	for k, v := range expected {
+		if aggregates[k] != v {
+			t.Errorf("expected %s %v, got %v", k, v, aggregates[k])
+		}
+	}
+
+	empty := aggregateMeasurements(nil)
+	if empty["measurement_count"] != 0 || empty["min"] != 0.0 || empty["mean"] != 0.0 {
+		t.Errorf("unexpected aggregates without values: %#v", empty)
+	}
+}
+
+func TestMeasurementsPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3}
+
+	cases := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{50, 3},
+		{90, 4.6},
+		{100, 5},
+	}
+	for _, c := range cases {
+		if got := measurementsPercentile(values, c.p); got < c.expected-1e-9 || got > c.expected+1e-9 {
+			t.Errorf("expected percentile %v to be %v, got %v", c.p, c.expected, got)
+		}
+	}
+
+	if values[0] != 5 {
+		t.Errorf("expected the values to be left unsorted, got %v", values)
+	}
+	if got := measurementsPercentile(nil, 95); got != 0 {
+		t.Errorf("expected 0 without values, got %v", got)
+	}
+}
+
+const testAccCheckAppOpticsMeasurementsDataSourceConfig = `
+data "appoptics_measurements" "foobar" {
+	name        = "system.cpu.utilization"
+	resolution  = 60
+	duration    = 3600
+	percentiles = [95]
+}`
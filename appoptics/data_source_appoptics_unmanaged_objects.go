@@ -0,0 +1,186 @@
+package appoptics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// appoptics_unmanaged_objects lists the dashboards, alerts and services that
+// neither carry the managed marker nor are known to be managed, e.g. to report
+// what is left to clean up in a shared account.
+func dataSourceAppOpticsUnmanagedObjects() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAppOpticsUnmanagedObjectsRead,
+
+		Schema: map[string]*schema.Schema{
+			"managed_marker": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"known_dashboard_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"known_alert_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"known_service_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"dashboards": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"alerts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"services": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppOpticsUnmanagedObjectsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	marker := meta.(*providerMeta).managedMarker
+	if v, ok := d.GetOk("managed_marker"); ok {
+		marker = v.(string)
+	}
+
+	spaces, err := spaceList(client)
+	if err != nil {
+		return fmt.Errorf("Error listing AppOptics spaces: %s", err)
+	}
+	alerts, err := alertList(client)
+	if err != nil {
+		return fmt.Errorf("Error listing AppOptics alerts: %s", err)
+	}
+	services, err := serviceList(client)
+	if err != nil {
+		return fmt.Errorf("Error listing AppOptics services: %s", err)
+	}
+
+	if err := d.Set("dashboards", unmanagedSpaces(spaces, marker, d.Get("known_dashboard_ids").(*schema.Set))); err != nil {
+		return err
+	}
+	if err := d.Set("alerts", unmanagedAlerts(alerts, marker, d.Get("known_alert_ids").(*schema.Set))); err != nil {
+		return err
+	}
+	if err := d.Set("services", unmanagedServices(services, marker, d.Get("known_service_ids").(*schema.Set))); err != nil {
+		return err
+	}
+
+	d.SetId(resource.UniqueId())
+	return nil
+}
+
+// Returns the spaces without the marker in their name and not known, by ID
+func unmanagedSpaces(spaces []*appoptics.Space, marker string, known *schema.Set) []interface{} {
+	sort.Slice(spaces, func(i, j int) bool { return spaces[i].ID < spaces[j].ID })
+
+	result := make([]interface{}, 0)
+	for _, space := range spaces {
+		if hasMarker(space.Name, marker) || known.Contains(space.ID) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"id":   space.ID,
+			"name": space.Name,
+		})
+	}
+	return result
+}
+
+// Returns the alerts without the marker in their description and not known, by
+// ID
+func unmanagedAlerts(alerts []*appoptics.Alert, marker string, known *schema.Set) []interface{} {
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+
+	result := make([]interface{}, 0)
+	for _, alert := range alerts {
+		if hasMarker(alert.Description, marker) || known.Contains(alert.ID) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"id":          alert.ID,
+			"name":        alert.Name,
+			"description": alert.Description,
+		})
+	}
+	return result
+}
+
+// Returns the services without the marker in their title and not known, by ID
+func unmanagedServices(services []*appoptics.Service, marker string, known *schema.Set) []interface{} {
+	sort.Slice(services, func(i, j int) bool { return services[i].ID < services[j].ID })
+
+	result := make([]interface{}, 0)
+	for _, service := range services {
+		if hasMarker(service.Title, marker) || known.Contains(service.ID) {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"id":    service.ID,
+			"title": service.Title,
+			"type":  service.Type,
+		})
+	}
+	return result
+}
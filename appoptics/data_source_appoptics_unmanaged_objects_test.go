@@ -0,0 +1,118 @@
+package appoptics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccAppOpticsUnmanagedObjectsDataSource(t *testing.T) {
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsUnmanagedObjectsDataSourceConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsUnmanagedObjectsExcludes("data.appoptics_unmanaged_objects.foobar", "alerts", "appoptics_alert.marked"),
+					testAccCheckAppOpticsUnmanagedObjectsExcludes("data.appoptics_unmanaged_objects.foobar", "alerts", "appoptics_alert.known"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAppOpticsUnmanagedObjectsExcludes(dataSource, list, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		ds, ok := s.RootModule().Resources[dataSource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", dataSource)
+		}
+
+		for k, v := range ds.Primary.Attributes {
+			if v == rs.Primary.ID && strings.HasPrefix(k, list+".") && strings.HasSuffix(k, ".id") {
+				return fmt.Errorf("%s is listed as unmanaged in %s", resourceName, k)
+			}
+		}
+		return nil
+	}
+}
+
+func TestUnmanagedAlerts(t *testing.T) {
+	alerts := []*appoptics.Alert{
+		{ID: 3, Name: "known"},
+		{ID: 2, Name: "marked", Description: "CPU is high [tf]"},
+		{ID: 4, Name: "other", Description: "CPU is high"},
+		{ID: 1, Name: "empty"},
+	}
+	known := schema.NewSet(schema.HashInt, []interface{}{3})
+
+	result := unmanagedAlerts(alerts, "[tf]", known)
+	if len(result) != 2 {
+		t.Fatalf("expected 2 unmanaged alerts, got %#v", result)
+	}
+	if id := result[0].(map[string]interface{})["id"]; id != 1 {
+		t.Errorf("expected the alerts to be sorted by ID, got %v first", id)
+	}
+	if id := result[1].(map[string]interface{})["id"]; id != 4 {
+		t.Errorf("expected alert 4, got %v", id)
+	}
+
+	if result := unmanagedAlerts(alerts, "", known); len(result) != 3 {
+		t.Errorf("expected every unknown alert without a marker, got %#v", result)
+	}
+}
+
+func TestUnmanagedSpacesAndServices(t *testing.T) {
+	spaces := []*appoptics.Space{{ID: 1, Name: "Overview [tf]"}, {ID: 2, Name: "Overview"}}
+	if result := unmanagedSpaces(spaces, "[tf]", schema.NewSet(schema.HashInt, nil)); len(result) != 1 || result[0].(map[string]interface{})["id"] != 2 {
+		t.Errorf("unexpected unmanaged spaces: %#v", result)
+	}
+
+	services := []*appoptics.Service{{ID: 1, Title: "Ops [tf]"}, {ID: 2, Title: "Ops", Type: "mail"}}
+	if result := unmanagedServices(services, "[tf]", schema.NewSet(schema.HashInt, nil)); len(result) != 1 || result[0].(map[string]interface{})["type"] != "mail" {
+		t.Errorf("unexpected unmanaged services: %#v", result)
+	}
+}
+
+func testAccCheckAppOpticsUnmanagedObjectsDataSourceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "appoptics_alert" "marked" {
+	name        = "%s-marked"
+	description = "Managed [tf-acc]"
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}
+
+resource "appoptics_alert" "known" {
+	name = "%s-known"
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}
+
+data "appoptics_unmanaged_objects" "foobar" {
+	managed_marker  = "[tf-acc]"
+	known_alert_ids = [appoptics_alert.known.id]
+
+	depends_on = [appoptics_alert.marked]
+}`, name, name)
+}
@@ -0,0 +1,96 @@
+package appoptics
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// metricDataProtectionDuration is how far back protect_metrics_with_data looks
+// for measurements, in seconds
+const metricDataProtectionDuration = 7 * 24 * 60 * 60
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// Refuses to delete a resource with deletion_protection. kind and name make up
+// the error message, along with the ID of the resource.
+func checkDeletionProtection(d *schema.ResourceData, kind, name string) error {
+	if d.Get("deletion_protection").(bool) {
+		return deletionProtectionError(kind, name, d.Id())
+	}
+	return nil
+}
+
+// Refuses to plan the removal of objects a resource manages, e.g. a metric of
+// appoptics_metrics_bulk. As on delete, the protection counts as applied, not
+// as configured.
+func checkDiffDeletionProtection(d *schema.ResourceDiff, kind, name, id string) error {
+	if protected, _ := d.GetChange("deletion_protection"); protected.(bool) {
+		return deletionProtectionError(kind, name, id)
+	}
+	return nil
+}
+
+// Refuses to remove objects a resource manages on update, for removals the
+// plan couldn't tell yet. The state is kept as it was, rather than taking the
+// new configuration, so that the objects stay managed.
+func checkUpdateDeletionProtection(d *schema.ResourceData, kind, name, id string) error {
+	if protected, _ := d.GetChange("deletion_protection"); protected.(bool) {
+		d.Partial(true)
+		return deletionProtectionError(kind, name, id)
+	}
+	return nil
+}
+
+// Names the protected object by name and ID, so that it can be told apart from
+// others of the same name
+func deletionProtectionError(kind, name, id string) error {
+	return fmt.Errorf("AppOptics %s %q (ID %s) is protected from deletion, set deletion_protection = false and apply before deleting it", kind, name, id)
+}
+
+// Refuses to delete a metric that still receives measurements, when the
+// provider sets protect_metrics_with_data
+func checkMetricDataProtection(meta interface{}, name string) error {
+	if !meta.(*providerMeta).protectMetricsWithData {
+		return nil
+	}
+
+	hasData, err := metricHasRecentData(meta.(*providerMeta).client, name)
+	if err != nil {
+		return fmt.Errorf("Error checking the measurements of AppOptics metric %s: %s", name, err)
+	}
+	if hasData {
+		return fmt.Errorf("AppOptics metric %s has measurements from the last %d days and protect_metrics_with_data is set, refusing to delete it", name, metricDataProtectionDuration/(24*60*60))
+	}
+	return nil
+}
+
+func metricHasRecentData(client *appoptics.Client, name string) (bool, error) {
+	query := url.Values{}
+	query.Set("resolution", strconv.Itoa(60*60))
+	query.Set("duration", strconv.Itoa(metricDataProtectionDuration))
+
+	measurements, err := measurementsRetrieve(client, name, query)
+	if err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, series := range measurements.Series {
+		if len(series.Measurements) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,61 @@
+package appoptics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"deletion_protection": deletionProtectionSchema(),
+	}
+
+	protected := schema.TestResourceDataRaw(t, s, map[string]interface{}{"deletion_protection": true})
+	if err := checkDeletionProtection(protected, "metric", "api.latency"); err == nil {
+		t.Error("expected a protected resource to be refused")
+	}
+
+	if err := checkDeletionProtection(protected, "metric", "api.latency"); err == nil || !strings.Contains(err.Error(), `"api.latency" (ID `) {
+		t.Errorf("expected the error to name the resource and its ID, got %v", err)
+	}
+
+	unprotected := schema.TestResourceDataRaw(t, s, map[string]interface{}{})
+	if err := checkDeletionProtection(unprotected, "metric", "api.latency"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestCheckUpdateDeletionProtection(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+	d := r.Data(&terraform.InstanceState{
+		ID:         "api",
+		Attributes: map[string]string{"id": "api", "name": "api.latency", "deletion_protection": "true"},
+	})
+	d.Set("name", "api.errors") //nolint
+
+	if err := checkUpdateDeletionProtection(d, "metric", "api.latency", d.Id()); err == nil {
+		t.Fatal("expected a protected resource to be refused")
+	}
+	// The refused update leaves the state as it was
+	if name := d.State().Attributes["name"]; name != "api.latency" {
+		t.Errorf("expected the state to keep name api.latency, got %s", name)
+	}
+}
+
+func TestCheckMetricDataProtectionDisabled(t *testing.T) {
+	// Without protect_metrics_with_data the API isn't asked at all
+	if err := checkMetricDataProtection(&providerMeta{}, "api.latency"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
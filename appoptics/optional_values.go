@@ -0,0 +1,61 @@
+package appoptics
+
+import (
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// attributeSetFunc reports whether the attribute at path was set in the
+// config, which tells an explicit zero apart from an unset attribute. Paths
+// are relative to the block being expanded. A nil func treats zero as unset.
+type attributeSetFunc func(path string) bool
+
+// Returns an attributeSetFunc looking attributes up under prefix, e.g.
+// "chart.0." for the first inline chart of a dashboard
+func resourceDataSetFunc(d *schema.ResourceData, prefix string) attributeSetFunc {
+	return func(path string) bool {
+		_, ok := d.GetOkExists(prefix + path)
+		return ok
+	}
+}
+
+// Returns nil for an unset value, so that it's left out of the request
+func expandOptionalFloat(v interface{}, path string, isSet attributeSetFunc) *float64 {
+	f, ok := v.(float64)
+	if !ok || math.IsNaN(f) {
+		return nil
+	}
+	if f == 0 && (isSet == nil || !isSet(path)) {
+		return nil
+	}
+	return &f
+}
+
+// Returns nil for an unset value, so that it's left out of the request
+func expandOptionalInt(v interface{}, path string, isSet attributeSetFunc) *int {
+	i, ok := v.(int)
+	if !ok {
+		return nil
+	}
+	if i == 0 && (isSet == nil || !isSet(path)) {
+		return nil
+	}
+	return &i
+}
+
+// Returns nil for a value the API doesn't have, which removes it from the
+// state rather than recording a zero
+func flattenOptionalFloat(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func flattenOptionalInt(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,60 @@
+package appoptics
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+)
+
+func TestExpandOptionalFloat(t *testing.T) {
+	set := func(path string) bool { return path == "min" }
+
+	if v := expandOptionalFloat(0.0, "min", set); v == nil || *v != 0 {
+		t.Errorf("expected an explicit zero, got %v", v)
+	}
+	if v := expandOptionalFloat(0.0, "max", set); v != nil {
+		t.Errorf("expected an unset zero to be nil, got %v", *v)
+	}
+	if v := expandOptionalFloat(0.0, "min", nil); v != nil {
+		t.Errorf("expected zero to be nil without a set func, got %v", *v)
+	}
+	if v := expandOptionalFloat(2.5, "max", nil); v == nil || *v != 2.5 {
+		t.Errorf("expected 2.5, got %v", v)
+	}
+	if v := expandOptionalFloat(math.NaN(), "min", set); v != nil {
+		t.Errorf("expected NaN to be nil, got %v", *v)
+	}
+}
+
+func TestSpaceChartDataSendsZero(t *testing.T) {
+	zero := 0.0
+	floor := 0
+	chart := &spaceChartData{
+		Chart:   appoptics.Chart{Name: "CPU"},
+		Min:     &zero,
+		Streams: []spaceChartStream{{Stream: appoptics.Stream{Metric: "cpu"}, Min: &floor}},
+	}
+
+	// appoptics.Chart leaves the zeros out, so they go with the extras
+	body, err := json.Marshal(chart.extras())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := decoded["min"]; !ok || v != 0.0 {
+		t.Errorf("expected min to be sent as 0: %s", body)
+	}
+	if _, ok := decoded["max"]; ok {
+		t.Errorf("expected max to be left out: %s", body)
+	}
+	stream := decoded["streams"].([]interface{})[0].(map[string]interface{})
+	if v, ok := stream["min"]; !ok || v != 0.0 {
+		t.Errorf("expected the stream min to be sent as 0: %s", body)
+	}
+}
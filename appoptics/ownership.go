@@ -0,0 +1,74 @@
+package appoptics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Teams sharing an account tell the objects they manage apart by the
+// provider's name_prefix and managed_marker. Names get the prefix, and the
+// marker is appended to the description of alerts and to the name of
+// dashboards and services, which don't have one. Both are stripped again when
+// reading, so they never show up in the configuration.
+
+// Returns the name as it is in AppOptics
+func (m *providerMeta) managedName(name string) string {
+	return m.namePrefix + name
+}
+
+// Returns the name as it is configured
+func (m *providerMeta) configuredName(name string) string {
+	return strings.TrimPrefix(name, m.namePrefix)
+}
+
+func (m *providerMeta) mark(s string) string {
+	if m.managedMarker == "" {
+		return s
+	}
+	if s == "" {
+		return m.managedMarker
+	}
+	return s + " " + m.managedMarker
+}
+
+func (m *providerMeta) unmark(s string) string {
+	if m.managedMarker == "" {
+		return s
+	}
+	if s == m.managedMarker {
+		return ""
+	}
+	return strings.TrimSuffix(s, " "+m.managedMarker)
+}
+
+// Returns whether s carries the managed marker. Without a marker configured
+// nothing is marked.
+func (m *providerMeta) isMarked(s string) bool {
+	return hasMarker(s, m.managedMarker)
+}
+
+func hasMarker(s, marker string) bool {
+	if marker == "" {
+		return false
+	}
+	return s == marker || strings.HasSuffix(s, " "+marker)
+}
+
+// Returns the name of a dashboard or service as it is in AppOptics
+func (m *providerMeta) managedTitle(title string) string {
+	return m.mark(m.managedName(title))
+}
+
+// Returns the name of a dashboard or service as it is configured
+func (m *providerMeta) configuredTitle(title string) string {
+	return m.configuredName(m.unmark(title))
+}
+
+// Refuses to import an object without the managed marker, unless the provider
+// allows it. kind and name only make up the error message.
+func (m *providerMeta) checkImport(kind, name string, marked bool) error {
+	if m.managedMarker == "" || m.allowUnmanagedImports || marked {
+		return nil
+	}
+	return fmt.Errorf("AppOptics %s %s doesn't carry the managed marker %q, it may belong to someone else. Set allow_unmanaged_imports in the provider to import it anyway", kind, name, m.managedMarker)
+}
@@ -0,0 +1,66 @@
+package appoptics
+
+import (
+	"testing"
+)
+
+func TestManagedTitle(t *testing.T) {
+	m := &providerMeta{namePrefix: "team-a/", managedMarker: "[tf]"}
+
+	cases := map[string]string{
+		"Overview": "team-a/Overview [tf]",
+		"":         "team-a/ [tf]",
+	}
+	for configured, managed := range cases {
+		if got := m.managedTitle(configured); got != managed {
+			t.Errorf("expected %q, got %q", managed, got)
+		}
+		if got := m.configuredTitle(managed); got != configured {
+			t.Errorf("expected %q to read back as %q, got %q", managed, configured, got)
+		}
+	}
+}
+
+func TestMark(t *testing.T) {
+	m := &providerMeta{managedMarker: "[tf]"}
+
+	if got := m.mark(""); got != "[tf]" {
+		t.Errorf("expected an empty description to become the marker, got %q", got)
+	}
+	if got := m.unmark("[tf]"); got != "" {
+		t.Errorf("expected the marker alone to read back empty, got %q", got)
+	}
+	if got := m.unmark(m.mark("CPU is high")); got != "CPU is high" {
+		t.Errorf("unexpected round trip: %q", got)
+	}
+	if !m.isMarked("CPU is high [tf]") || m.isMarked("CPU is high") || m.isMarked("CPU is high[tf]") {
+		t.Error("unexpected isMarked result")
+	}
+
+	unmarked := &providerMeta{}
+	if got := unmarked.mark("CPU is high"); got != "CPU is high" {
+		t.Errorf("expected no change without a marker, got %q", got)
+	}
+	if unmarked.isMarked("CPU is high [tf]") {
+		t.Error("expected nothing to be marked without a marker")
+	}
+}
+
+func TestCheckImport(t *testing.T) {
+	m := &providerMeta{managedMarker: "[tf]"}
+	if err := m.checkImport("alert", "cpu", false); err == nil {
+		t.Error("expected an unmarked import to be refused")
+	}
+	if err := m.checkImport("alert", "cpu", true); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	m.allowUnmanagedImports = true
+	if err := m.checkImport("alert", "cpu", false); err != nil {
+		t.Errorf("unexpected error with allow_unmanaged_imports: %s", err)
+	}
+
+	if err := (&providerMeta{}).checkImport("alert", "cpu", false); err != nil {
+		t.Errorf("unexpected error without a marker: %s", err)
+	}
+}
@@ -0,0 +1,34 @@
+package appoptics
+
+import (
+	"fmt"
+
+	"github.com/appoptics/appoptics-api-go"
+)
+
+// listPageLength is how many objects each request of a list API asks for
+const listPageLength = 100
+
+// Requests every page of a list API at path. Each page is decoded into what
+// newPage returns, then handed to add, which keeps its objects and returns how
+// many there were and how many the API found in total.
+func listPages(client *appoptics.Client, path string, newPage func() interface{}, add func(page interface{}) (int, int)) error {
+	offset := 0
+	for {
+		req, err := client.NewRequest("GET", fmt.Sprintf("%s?offset=%d&length=%d", path, offset, listPageLength), nil)
+		if err != nil {
+			return err
+		}
+
+		page := newPage()
+		if _, err := client.Do(req, page); err != nil {
+			return err
+		}
+		count, found := add(page)
+
+		offset += count
+		if count == 0 || offset >= found {
+			return nil
+		}
+	}
+}
@@ -18,20 +18,77 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("APPOPTICS_TOKEN", nil),
 				Description: "The auth token for the AppOptics account.",
 			},
+			"prefetch_metrics": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "List all metrics of the account once per run and refresh appoptics_metric resources from that list.",
+			},
+			"name_prefix": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Prefix added to the names of the dashboards, alerts and services the provider creates.",
+			},
+			"managed_marker": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Marker added to the dashboards, alerts and services the provider creates, to tell them apart from the ones managed otherwise.",
+			},
+			"allow_unmanaged_imports": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Import dashboards, alerts and services that don't carry the managed_marker.",
+			},
+			"protect_metrics_with_data": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to delete metrics that have measurements from the last 7 days.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"appoptics_dashboard":            resourceAppOpticsSpace(),      // name is legacy from Librato
-			"appoptics_dashboard_chart":      resourceAppOpticsSpaceChart(), // name is legacy from Librato
-			"appoptics_metric":               resourceAppOpticsMetric(),
-			"appoptics_alert":                resourceAppOpticsAlert(),
-			"appoptics_notification_service": resourceAppOpticsService(), // changed from API name to differentiate w/ APM Services
+			"appoptics_dashboard":                resourceAppOpticsSpace(),      // name is legacy from Librato
+			"appoptics_dashboard_chart":          resourceAppOpticsSpaceChart(), // name is legacy from Librato
+			"appoptics_dashboard_json":           resourceAppOpticsSpaceJSON(),  // name is legacy from Librato
+			"appoptics_metric":                   resourceAppOpticsMetric(),
+			"appoptics_metrics_bulk":             resourceAppOpticsMetricsBulk(),
+			"appoptics_measurement":              resourceAppOpticsMeasurement(),
+			"appoptics_alert":                    resourceAppOpticsAlert(),
+			"appoptics_alert_group":              resourceAppOpticsAlertGroup(),
+			"appoptics_alert_service_attachment": resourceAppOpticsAlertServiceAttachment(),
+			"appoptics_notification_service":     resourceAppOpticsService(), // changed from API name to differentiate w/ APM Services
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"appoptics_alert_status":      dataSourceAppOpticsAlertStatus(),
+			"appoptics_measurements":      dataSourceAppOpticsMeasurements(),
+			"appoptics_unmanaged_objects": dataSourceAppOpticsUnmanagedObjects(),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// providerMeta is what the resources receive as meta: the API client and the
+// caches that live for the duration of a run
+type providerMeta struct {
+	client  *appoptics.Client
+	charts  *spaceChartCache
+	spaces  *spaceExistenceCache
+	metrics *metricCache // nil unless prefetch_metrics is set
+
+	protectMetricsWithData bool
+
+	// see ownership.go
+	namePrefix            string
+	managedMarker         string
+	allowUnmanagedImports bool
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	var url string
 	if appOpticsURL := os.Getenv("APPOPTICS_URL"); appOpticsURL != "" {
@@ -39,13 +96,28 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	} else {
 		url = "https://api.appoptics.com/v1/"
 	}
+	var client *appoptics.Client
 	if do_http_debug := os.Getenv("TF_AO_DEBUG"); do_http_debug != "" {
-		return appoptics.NewClient(d.Get("token").(string),
+		client = appoptics.NewClient(d.Get("token").(string),
 			appoptics.BaseURLClientOption(url),
 			appoptics.SetDebugMode(),
-		), nil
+		)
 	} else {
-		return appoptics.NewClient(d.Get("token").(string),
-			appoptics.BaseURLClientOption(url)), nil
+		client = appoptics.NewClient(d.Get("token").(string),
+			appoptics.BaseURLClientOption(url))
+	}
+
+	meta := &providerMeta{
+		client:                 client,
+		charts:                 newSpaceChartCache(),
+		spaces:                 newSpaceExistenceCache(),
+		protectMetricsWithData: d.Get("protect_metrics_with_data").(bool),
+		namePrefix:             d.Get("name_prefix").(string),
+		managedMarker:          d.Get("managed_marker").(string),
+		allowUnmanagedImports:  d.Get("allow_unmanaged_imports").(bool),
+	}
+	if d.Get("prefetch_metrics").(bool) {
+		meta.metrics = newMetricCache()
 	}
+	return meta, nil
 }
@@ -1,7 +1,6 @@
 package appoptics
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"math"
@@ -9,9 +8,9 @@ import (
 	"time"
 
 	"github.com/appoptics/appoptics-api-go"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func resourceAppOpticsAlert() *schema.Resource {
@@ -21,7 +20,23 @@ func resourceAppOpticsAlert() *schema.Resource {
 		Update: resourceAppOpticsAlertUpdate,
 		Delete: resourceAppOpticsAlertDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAppOpticsAlertImport,
+		},
+
+		CustomizeDiff: resourceAppOpticsAlertCustomizeDiff,
+
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceAppOpticsAlertResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAppOpticsAlertStateUpgradeV0,
+				Version: 0,
+			},
+			{
+				Type:    resourceAppOpticsAlertResourceV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAppOpticsAlertStateUpgradeV1,
+				Version: 1,
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,211 +62,161 @@ func resourceAppOpticsAlert() *schema.Resource {
 			"services": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Set:      schema.HashString,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				Set: schema.HashInt,
 			},
+			"manage_services": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"clear_on_update": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"last_cleared_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"deletion_protection": deletionProtectionSchema(),
 			"condition": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     resourceAppOpticsAlertConditionResource(),
+			},
+			"mute_window": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     resourceAppOpticsAlertMuteWindowResource(),
+			},
+			"muted": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"runbook_url": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsURLWithHTTPorHTTPS,
+				ConflictsWith: []string{"attributes"},
+			},
+			"attributes": {
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Deprecated:    "Use runbook_url instead",
+				ConflictsWith: []string{"runbook_url"},
+			},
+		},
+	}
+}
+
+func resourceAppOpticsAlertCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("manage_services").(bool) && d.Get("services").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("services can't be set when manage_services is false, attach them with appoptics_alert_service_attachment instead")
+	}
+
+	// Show in the plan that the alert gets cleared
+	if d.Id() != "" && d.Get("clear_on_update").(bool) && d.HasChange("condition") {
+		if err := d.SetNewComputed("last_cleared_at"); err != nil {
+			return err
+		}
+	}
+
+	// Muting is decided at plan time, so that the plan shows which alerts
+	// the next apply mutes or unmutes
+	if d.NewValueKnown("mute_window") {
+		muted, err := alertMutedAt(d.Get("mute_window").([]interface{}), time.Now())
+		if err != nil {
+			return err
+		}
+		if d.Id() == "" || muted != d.Get("muted").(bool) {
+			log.Printf("[INFO] AppOptics alert %s muted by its mute windows: %t", d.Get("name"), muted)
+			return d.SetNew("muted", muted)
+		}
+	}
+	return nil
+}
+
+func resourceAppOpticsAlertConditionResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metric_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag": {
+				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"type": {
+						"name": {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
-						"metric_name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"tag": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Type:     schema.TypeString,
-										Optional: true,
-									},
-									"grouped": {
-										Type:     schema.TypeBool,
-										Optional: true,
-									},
-									"dynamic": {
-										Type:     schema.TypeBool,
-										Optional: true,
-									},
-									"values": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem:     &schema.Schema{Type: schema.TypeString},
-									},
-								},
-							},
-						},
-						"detect_reset": {
+						"grouped": {
 							Type:     schema.TypeBool,
 							Optional: true,
 						},
-						"duration": {
-							Type:     schema.TypeInt,
-							Optional: true,
-						},
-						"threshold": {
-							Type:     schema.TypeFloat,
+						"dynamic": {
+							Type:     schema.TypeBool,
 							Optional: true,
 						},
-						"summary_function": {
-							Type:     schema.TypeString,
+						"values": {
+							Type:     schema.TypeList,
 							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
 						},
 					},
 				},
-				Set: resourceAppOpticsAlertConditionsHash,
 			},
-			"attributes": {
-				Type:     schema.TypeMap,
+			"detect_reset": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"duration": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"threshold": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"summary_function": {
+				Type:     schema.TypeString,
 				Optional: true,
 			},
 		},
 	}
 }
 
-func resourceAppOpticsAlertConditionsHash(v interface{}) int {
-	var buf bytes.Buffer
-	m := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%s-", m["type"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["metric_name"].(string)))
-
-	tags, present := m["tag"].([]interface{})
-	if present && len(tags) > 0 {
-		buf.WriteString(fmt.Sprintf("%d-", alertConditionsTagsHash(tags)))
-	}
-
-	detectReset, present := m["detect_reset"]
-	if present {
-		buf.WriteString(fmt.Sprintf("%t-", detectReset.(bool)))
-	}
-
-	duration, present := m["duration"]
-	if present {
-		buf.WriteString(fmt.Sprintf("%d-", duration.(int)))
-	}
-
-	threshold, present := m["threshold"]
-	if present {
-		buf.WriteString(fmt.Sprintf("%f-", threshold.(float64)))
-	}
-
-	summaryFunction, present := m["summary_function"]
-	if present {
-		buf.WriteString(fmt.Sprintf("%s-", summaryFunction.(string)))
-	}
-
-	return hashcode.String(buf.String())
-}
-
-func alertConditionsTagsHash(tags []interface{}) int {
-	var buf bytes.Buffer
-	for _, v := range tags {
-		m := v.(map[string]interface{})
-		buf.WriteString(fmt.Sprintf("%s-", m["name"]))
-		buf.WriteString(fmt.Sprintf("%s-", m["grouped"]))
-		buf.WriteString(fmt.Sprintf("%d-", alertConditionsTagsValuesHash(m["values"].([]interface{}))))
-	}
-
-	return hashcode.String(buf.String())
-}
-
-func alertConditionsTagsValuesHash(s []interface{}) int {
-	var buf bytes.Buffer
-	for _, v := range s {
-		buf.WriteString(fmt.Sprintf("%s-", v))
-	}
-
-	return hashcode.String(buf.String())
-}
-
 func resourceAppOpticsAlertCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	alert := appoptics.AlertRequest{
-		Name: d.Get("name").(string),
-	}
-	if v, ok := d.GetOk("description"); ok {
-		alert.Description = v.(string)
+		Name:        meta.(*providerMeta).managedName(d.Get("name").(string)),
+		Description: meta.(*providerMeta).mark(d.Get("description").(string)),
 	}
 	// GetOK returns not OK for false boolean values, use Get
-	var active bool = d.Get("active").(bool)
+	var active bool = d.Get("active").(bool) && !d.Get("muted").(bool)
 	alert.Active = &active
 	if v, ok := d.GetOk("rearm_seconds"); ok {
 		alert.RearmSeconds = v.(int)
 	}
-	if _, ok := d.GetOk("services"); ok {
-		vs := d.Get("services").(*schema.Set)
-		services := make([]int, vs.Len())
-		for i, serviceID := range vs.List() {
-			var err error
-			services[i], err = strconv.Atoi(serviceID.(string))
-			if err != nil {
-				return fmt.Errorf("Error: %s", err)
-			}
-		}
-		alert.Services = services
+	if v, ok := d.GetOk("services"); ok && d.Get("manage_services").(bool) {
+		alert.Services = expandServices(v.(*schema.Set))
 	}
 	if v, ok := d.GetOk("condition"); ok {
-		vs := v.(*schema.Set)
-		conditions := make([]*appoptics.AlertCondition, vs.Len())
-
-		for i, conditionDataM := range vs.List() {
-			conditionData := conditionDataM.(map[string]interface{})
-			condition := appoptics.AlertCondition{}
-
-			if v, ok := conditionData["type"].(string); ok && v != "" {
-				condition.Type = v
-			}
-			if v, ok := conditionData["threshold"].(float64); ok && !math.IsNaN(v) {
-				condition.Threshold = v
-			}
-			if v, ok := conditionData["metric_name"].(string); ok && v != "" {
-				condition.MetricName = v
-			}
-			if v, ok := conditionData["tag"].([]interface{}); ok {
-				tags := make([]*appoptics.Tag, len(v))
-				for i, tagData := range v {
-					tag := appoptics.Tag{}
-					tag.Grouped = tagData.(map[string]interface{})["grouped"].(bool)
-					tag.Dynamic = tagData.(map[string]interface{})["dynamic"].(bool)
-					tag.Name = tagData.(map[string]interface{})["name"].(string)
-					values := tagData.(map[string]interface{})["values"].([]interface{})
-					valuesInStrings := make([]string, len(values))
-					for i, v := range values {
-						valuesInStrings[i] = v.(string)
-					}
-					tag.Values = valuesInStrings
-					tags[i] = &tag
-				}
-
-				condition.Tags = tags
-			}
-			if v, ok := conditionData["duration"].(int); ok {
-				condition.Duration = v
-			}
-			if v, ok := conditionData["summary_function"].(string); ok && v != "" {
-				condition.SummaryFunction = v
-			}
-			conditions[i] = &condition
-		}
-
-		alert.Conditions = conditions
+		alert.Conditions = resourceAppOpticsAlertConditionsExpand(v.([]interface{}))
 	}
-	if v, ok := d.GetOk("attributes"); ok {
-		attributeData := v.(map[string]interface{})
-		if len(attributeData) > 1 {
-			return fmt.Errorf("Only one set of attributes per alert is supported")
-		} else if len(attributeData) == 1 {
-			// The only attribute here should be the runbook_url
-			alert.Attributes = attributeData
-		}
+	if attributes := resourceAppOpticsAlertAttributesExpand(d); len(attributes) > 0 {
+		alert.Attributes = attributes
 	}
 
 	alertResult, err := client.AlertsService().Create(&alert)
@@ -281,7 +246,7 @@ func resourceAppOpticsAlertCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceAppOpticsAlertRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
@@ -298,13 +263,17 @@ func resourceAppOpticsAlertRead(d *schema.ResourceData, meta interface{}) error
 	}
 	log.Printf("[INFO] Received AppOptics Alert: %s", alert.Name)
 
-	d.Set("name", alert.Name) //nolint
+	d.Set("name", meta.(*providerMeta).configuredName(alert.Name)) //nolint
 
-	if err := d.Set("description", alert.Description); err != nil {
+	if err := d.Set("description", meta.(*providerMeta).unmark(alert.Description)); err != nil {
 		return err
 	}
 
-	if err := d.Set("active", alert.Active); err != nil {
+	// An alert deactivated by its mute windows is still active as far as the
+	// configuration is concerned
+	if d.Get("muted").(bool) && alert.Active != nil && !*alert.Active {
+		log.Printf("[DEBUG] AppOptics Alert %s is muted", d.Id())
+	} else if err := d.Set("active", alert.Active); err != nil {
 		return err
 	}
 
@@ -314,10 +283,14 @@ func resourceAppOpticsAlertRead(d *schema.ResourceData, meta interface{}) error
 
 	// Since the following aren't simple terraform types (TypeList), it's best to
 	// catch the error returned from the d.Set() function, and handle accordingly.
-	services := flattenServices(d, alert.Services)
-	// TODO: does this need `schema.NewSet(...)`?
-	if err := d.Set("services", schema.NewSet(schema.HashString, services)); err != nil {
-		return err
+	// Services attached elsewhere, e.g. with appoptics_alert_service_attachment,
+	// would otherwise show up as drift. States from before manage_services
+	// don't have it yet and are managed.
+	if manage, ok := d.GetOkExists("manage_services"); !ok || manage.(bool) {
+		services := flattenServices(d, alert.Services)
+		if err := d.Set("services", schema.NewSet(schema.HashInt, services)); err != nil {
+			return err
+		}
 	}
 
 	conditions := flattenCondition(d, alert.Conditions)
@@ -325,18 +298,116 @@ func resourceAppOpticsAlertRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	if err := d.Set("attributes", alert.Attributes); err != nil {
-		return err
+	// The legacy attributes map and runbook_url describe the same thing, only
+	// the one in use is set
+	attributes := flattenAlertAttributes(alert.Attributes)
+	if _, ok := d.GetOk("attributes"); ok {
+		if err := d.Set("attributes", attributes); err != nil {
+			return err
+		}
+	} else {
+		runbookURL, _ := attributes["runbook_url"].(string)
+		if err := d.Set("runbook_url", runbookURL); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// Returns the attributes to send for the alert. Attributes removed since the
+// last apply are sent as null, which clears them.
+func resourceAppOpticsAlertAttributesExpand(d *schema.ResourceData) map[string]interface{} {
+	attributes := make(map[string]interface{})
+
+	oldAttributes, _ := d.GetChange("attributes")
+	for k := range oldAttributes.(map[string]interface{}) {
+		attributes[k] = nil
+	}
+	if oldRunbookURL, _ := d.GetChange("runbook_url"); oldRunbookURL.(string) != "" {
+		attributes["runbook_url"] = nil
+	}
+
+	for k, v := range d.Get("attributes").(map[string]interface{}) {
+		attributes[k] = v
+	}
+	if v, ok := d.GetOk("runbook_url"); ok {
+		attributes["runbook_url"] = v.(string)
+	}
+
+	return attributes
+}
+
+// Drops the attributes the API reports as cleared
+func flattenAlertAttributes(in map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		if v != nil && v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func resourceAppOpticsAlertConditionsExpand(in []interface{}) []*appoptics.AlertCondition {
+	conditions := make([]*appoptics.AlertCondition, len(in))
+
+	for i, conditionDataM := range in {
+		conditionData := conditionDataM.(map[string]interface{})
+		condition := appoptics.AlertCondition{}
+
+		if v, ok := conditionData["type"].(string); ok && v != "" {
+			condition.Type = v
+		}
+		if v, ok := conditionData["threshold"].(float64); ok && !math.IsNaN(v) {
+			condition.Threshold = v
+		}
+		if v, ok := conditionData["metric_name"].(string); ok && v != "" {
+			condition.MetricName = v
+		}
+		if v, ok := conditionData["tag"].([]interface{}); ok {
+			tags := make([]*appoptics.Tag, len(v))
+			for i, tagData := range v {
+				tag := appoptics.Tag{}
+				tag.Grouped = tagData.(map[string]interface{})["grouped"].(bool)
+				tag.Dynamic = tagData.(map[string]interface{})["dynamic"].(bool)
+				tag.Name = tagData.(map[string]interface{})["name"].(string)
+				values := tagData.(map[string]interface{})["values"].([]interface{})
+				valuesInStrings := make([]string, len(values))
+				for i, v := range values {
+					valuesInStrings[i] = v.(string)
+				}
+				tag.Values = valuesInStrings
+				tags[i] = &tag
+			}
+
+			condition.Tags = tags
+		}
+		if v, ok := conditionData["duration"].(int); ok {
+			condition.Duration = v
+		}
+		if v, ok := conditionData["summary_function"].(string); ok && v != "" {
+			condition.SummaryFunction = v
+		}
+		conditions[i] = &condition
+	}
+
+	return conditions
+}
+
+func expandServices(in *schema.Set) []int {
+	services := make([]int, 0, in.Len())
+	for _, serviceID := range in.List() {
+		services = append(services, serviceID.(int))
+	}
+	return services
+}
+
 func flattenServices(d *schema.ResourceData, services []*appoptics.Service) []interface{} {
 	retServices := make([]interface{}, 0, len(services))
 
 	for _, serviceData := range services {
-		retServices = append(retServices, fmt.Sprintf("%.d", serviceData.ID))
+		retServices = append(retServices, serviceData.ID)
 	}
 
 	return retServices
@@ -387,7 +458,7 @@ func flattenConditionTagsValues(in []string) []interface{} {
 }
 
 func resourceAppOpticsAlertUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	id, err := strconv.ParseInt(d.Id(), 10, 0)
 	if err != nil {
@@ -402,85 +473,32 @@ func resourceAppOpticsAlertUpdate(d *schema.ResourceData, meta interface{}) erro
 	alert.ID = int(id)
 
 	if d.HasChange("name") {
-		alert.Name = d.Get("name").(string)
+		alert.Name = meta.(*providerMeta).managedName(d.Get("name").(string))
 	}
 	if d.HasChange("description") {
-		alert.Description = d.Get("description").(string)
+		alert.Description = meta.(*providerMeta).mark(d.Get("description").(string))
 	}
-	if d.HasChange("active") {
-		var active bool = d.Get("active").(bool)
+	if d.HasChanges("active", "muted") {
+		var active bool = d.Get("active").(bool) && !d.Get("muted").(bool)
 		alert.Active = &active
 	}
 	if d.HasChange("rearm_seconds") {
 		alert.RearmSeconds = d.Get("rearm_seconds").(int)
 	}
-	if d.HasChange("services") {
-		vs := d.Get("services").(*schema.Set)
-		services := make([]int, vs.Len())
-		for i, serviceID := range vs.List() {
-			var err error
-			services[i], err = strconv.Atoi(serviceID.(string))
-			if err != nil {
-				return fmt.Errorf("Error: %s", err)
-			}
-		}
-		alert.Services = services
+	// Unmanaged services are left as they are, alertToAlertRequest carries
+	// them over
+	if d.HasChange("services") && d.Get("manage_services").(bool) {
+		alert.Services = expandServices(d.Get("services").(*schema.Set))
 	}
 
 	// We always have to send the conditions hash, from the API docs:
 	//
 	// NOTE: This method requires the conditions hash.
 	// If conditions is not included in the payload, the alert conditions will be removed.
-	vs := d.Get("condition").(*schema.Set)
-	conditions := make([]*appoptics.AlertCondition, vs.Len())
-
-	for i, conditionDataM := range vs.List() {
-		conditionData := conditionDataM.(map[string]interface{})
-		condition := appoptics.AlertCondition{}
-
-		if v, ok := conditionData["type"].(string); ok && v != "" {
-			condition.Type = v
-		}
-		if v, ok := conditionData["threshold"].(float64); ok && !math.IsNaN(v) {
-			condition.Threshold = v
-		}
-		if v, ok := conditionData["metric_name"].(string); ok && v != "" {
-			condition.MetricName = v
-		}
-		if v, ok := conditionData["tag"].([]interface{}); ok {
-			tags := make([]*appoptics.Tag, len(v))
-			for i, tagData := range v {
-				tag := appoptics.Tag{}
-				tag.Grouped = tagData.(map[string]interface{})["grouped"].(bool)
-				tag.Dynamic = tagData.(map[string]interface{})["dynamic"].(bool)
-				tag.Name = tagData.(map[string]interface{})["name"].(string)
-				values := tagData.(map[string]interface{})["values"].([]interface{})
-				valuesInStrings := make([]string, len(values))
-				for i, v := range values {
-					valuesInStrings[i] = v.(string)
-				}
-				tag.Values = valuesInStrings
-				tags[i] = &tag
-			}
+	alert.Conditions = resourceAppOpticsAlertConditionsExpand(d.Get("condition").([]interface{}))
 
-			condition.Tags = tags
-		}
-		if v, ok := conditionData["duration"].(int); ok {
-			condition.Duration = v
-		}
-		if v, ok := conditionData["summary_function"].(string); ok && v != "" {
-			condition.SummaryFunction = v
-		}
-		conditions[i] = &condition
-	}
-	alert.Conditions = conditions
-
-	if d.HasChange("attributes") {
-		attributeData := d.Get("attributes").(map[string]interface{})
-		if len(attributeData) == 0 {
-			return fmt.Errorf("No attributes found in attributes block")
-		}
-		alert.Attributes = attributeData
+	if d.HasChanges("attributes", "runbook_url") {
+		alert.Attributes = resourceAppOpticsAlertAttributesExpand(d)
 	}
 
 	log.Printf("[INFO] Updating AppOptics alert: %s", alert.Name)
@@ -491,6 +509,11 @@ func resourceAppOpticsAlertUpdate(d *schema.ResourceData, meta interface{}) erro
 
 	log.Printf("[INFO] Updated AppOptics alert %d", id)
 
+	// A triggered alert stays open with the notification services until it's
+	// cleared, even when the new conditions no longer match. Clearing it before
+	// the new conditions are in place would be undone by the old ones.
+	clear := d.Get("clear_on_update").(bool) && d.HasChange("condition")
+
 	// Wait for propagation since AppOptics updates are eventually consistent
 	wait := resource.StateChangeConf{
 		Pending:                   []string{fmt.Sprintf("%t", false)},
@@ -504,7 +527,12 @@ func resourceAppOpticsAlertUpdate(d *schema.ResourceData, meta interface{}) erro
 			if getErr != nil {
 				return changedAlert, "", getErr
 			}
-			return changedAlert, "true", nil
+			if !clear {
+				return changedAlert, "true", nil
+			}
+			applied := alertConditionsApplied(alert.Conditions, changedAlert.Conditions)
+			log.Printf("[DEBUG] Updated AppOptics Alert %d conditions applied: %t", id, applied)
+			return changedAlert, fmt.Sprintf("%t", applied), nil
 		},
 	}
 
@@ -513,16 +541,116 @@ func resourceAppOpticsAlertUpdate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("Failed updating AppOptics Alert %d: %s", id, err)
 	}
 
+	if clear {
+		if err := resourceAppOpticsAlertClear(client, int(id)); err != nil {
+			// Keeping the old conditions in the state plans the update, and
+			// with it the clear, again
+			d.Partial(true)
+			return fmt.Errorf("AppOptics alert %d was updated but couldn't be cleared: %s", id, err)
+		}
+		if err := d.Set("last_cleared_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
 	return resourceAppOpticsAlertRead(d, meta)
 }
 
+// Reports whether every condition sent is among the current conditions of the
+// alert. Only the fields that were sent are compared, so that values the API
+// fills in or normalizes don't keep the update waiting.
+func alertConditionsApplied(sent, current []*appoptics.AlertCondition) bool {
+	if len(sent) != len(current) {
+		return false
+	}
+
+	used := make([]bool, len(current))
+	for _, want := range sent {
+		found := false
+		for i, have := range current {
+			if !used[i] && alertConditionMatches(want, have) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func alertConditionMatches(want, have *appoptics.AlertCondition) bool {
+	if want.Type != have.Type || want.MetricName != have.MetricName || want.Threshold != have.Threshold {
+		return false
+	}
+	if want.SummaryFunction != "" && want.SummaryFunction != have.SummaryFunction {
+		return false
+	}
+	if want.Duration != 0 && want.Duration != have.Duration {
+		return false
+	}
+
+	for _, wantTag := range want.Tags {
+		found := false
+		for _, haveTag := range have.Tags {
+			if haveTag.Name == wantTag.Name && stringSetsEqual(haveTag.Values, wantTag.Values) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		counts[v]--
+		if counts[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func resourceAppOpticsAlertClear(client *appoptics.Client, id int) error {
+	req, err := client.NewRequest("POST", fmt.Sprintf("alerts/%d/clear", id), nil)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Clearing AppOptics alert %d", id)
+	if _, err := client.Do(req, nil); err != nil {
+		log.Printf("[WARN] Clearing AppOptics alert %d failed: %s", id, err)
+		return err
+	}
+	log.Printf("[INFO] Cleared AppOptics alert %d", id)
+
+	return nil
+}
+
 func resourceAppOpticsAlertDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
 	}
 
+	if err := checkDeletionProtection(d, "alert", d.Get("name").(string)); err != nil {
+		return err
+	}
+
 	log.Printf("[INFO] Deleting Alert: %d", id)
 	err = client.AlertsService().Delete(int(id))
 	if err != nil {
@@ -546,6 +674,39 @@ func resourceAppOpticsAlertDelete(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+// Imports an alert by its ID, refusing alerts without the managed marker unless
+// the provider allows them
+func resourceAppOpticsAlertImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing AppOptics alert ID %s: %s", d.Id(), err)
+	}
+
+	alert, err := meta.(*providerMeta).client.AlertsService().Retrieve(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading AppOptics alert %s: %s", d.Id(), err)
+	}
+	if err := meta.(*providerMeta).checkImport("alert", alert.Name, meta.(*providerMeta).isMarked(alert.Description)); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns every alert of the account, following the pagination of the API
+func alertList(client *appoptics.Client) ([]*appoptics.Alert, error) {
+	alerts := make([]*appoptics.Alert, 0)
+	err := listPages(client, "alerts", func() interface{} { return &appoptics.AlertsListResponse{} }, func(page interface{}) (int, int) {
+		p := page.(*appoptics.AlertsListResponse)
+		alerts = append(alerts, p.Alerts...)
+		return len(p.Alerts), p.Query.Found
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
 // used to deal w/ differing structures in API create/read
 func alertToAlertRequest(a *appoptics.Alert) *appoptics.AlertRequest {
 	aReq := &appoptics.AlertRequest{}
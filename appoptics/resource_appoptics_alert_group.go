@@ -0,0 +1,462 @@
+package appoptics
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// alertGroupValuePlaceholder is replaced by the tag value in name_pattern
+const alertGroupValuePlaceholder = "{value}"
+
+// appoptics_alert_group manages one alert per tag value, all stamped from the
+// same template. Alerts are tracked in alert_ids by their tag value, so adding
+// or removing a value only creates or deletes that value's alert.
+func resourceAppOpticsAlertGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAppOpticsAlertGroupCreate,
+		Read:   resourceAppOpticsAlertGroupRead,
+		Update: resourceAppOpticsAlertGroupUpdate,
+		Delete: resourceAppOpticsAlertGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAppOpticsAlertGroupImport,
+		},
+
+		CustomizeDiff: resourceAppOpticsAlertGroupCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name_pattern": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(regexp.QuoteMeta(alertGroupValuePlaceholder)),
+					fmt.Sprintf("the name pattern needs the %s placeholder to give each alert a unique name", alertGroupValuePlaceholder),
+				),
+			},
+			"tag_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"tag_values": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"rearm_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  600,
+			},
+			"services": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				Set: schema.HashInt,
+			},
+			"condition": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     resourceAppOpticsAlertConditionResource(),
+			},
+			"alert_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"drifted_values": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+}
+
+func resourceAppOpticsAlertGroupCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// The alerts of removed values are deleted, unless protected
+	if d.NewValueKnown("tag_values") {
+		values := d.Get("tag_values").(*schema.Set)
+		removed := make([]string, 0)
+		alertIDs := d.Get("alert_ids").(map[string]interface{})
+		for value := range alertIDs {
+			if !values.Contains(value) {
+				removed = append(removed, value)
+			}
+		}
+		sort.Strings(removed)
+		for _, value := range removed {
+			if err := checkDiffDeletionProtection(d, "alert", strings.Replace(d.Get("name_pattern").(string), alertGroupValuePlaceholder, value, -1), alertIDs[value].(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Alerts changed outside of Terraform are brought back to the template
+	if d.Get("drifted_values").(*schema.Set).Len() > 0 {
+		if err := d.SetNew("drifted_values", []interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	// A value without an alert, new or deleted outside of Terraform, gets one
+	alertIDs := d.Get("alert_ids").(map[string]interface{})
+	for _, value := range d.Get("tag_values").(*schema.Set).List() {
+		if _, ok := alertIDs[value.(string)]; !ok {
+			return d.SetNewComputed("alert_ids")
+		}
+	}
+	if d.HasChange("tag_values") {
+		return d.SetNewComputed("alert_ids")
+	}
+	return nil
+}
+
+// Returns the alert of one tag value
+func resourceAppOpticsAlertGroupExpand(d *schema.ResourceData, meta interface{}, value string) *appoptics.AlertRequest {
+	active := d.Get("active").(bool)
+	alert := &appoptics.AlertRequest{
+		Name:         meta.(*providerMeta).managedName(strings.Replace(d.Get("name_pattern").(string), alertGroupValuePlaceholder, value, -1)),
+		Description:  meta.(*providerMeta).mark(d.Get("description").(string)),
+		Active:       &active,
+		RearmSeconds: d.Get("rearm_seconds").(int),
+		Services:     expandServices(d.Get("services").(*schema.Set)),
+		Conditions:   resourceAppOpticsAlertConditionsExpand(d.Get("condition").([]interface{})),
+	}
+	alertGroupTagConditions(alert.Conditions, d.Get("tag_name").(string), value)
+
+	return alert
+}
+
+// Filters every condition on the tag value, replacing the values of any filter
+// of the template on the same tag
+func alertGroupTagConditions(conditions []*appoptics.AlertCondition, tagName, value string) {
+	for _, condition := range conditions {
+		replaced := false
+		for i, existing := range condition.Tags {
+			if existing.Name == tagName {
+				tag := *existing
+				tag.Values = []string{value}
+				condition.Tags[i] = &tag
+				replaced = true
+			}
+		}
+		if !replaced {
+			condition.Tags = append(condition.Tags, &appoptics.Tag{Name: tagName, Values: []string{value}})
+		}
+	}
+}
+
+// Reports whether an alert of the group no longer matches what the template
+// makes of its tag value
+func alertGroupAlertDrifted(expected *appoptics.AlertRequest, alert *appoptics.Alert) bool {
+	actual := alertToAlertRequest(alert)
+	if actual.Name != expected.Name || actual.Description != expected.Description || actual.RearmSeconds != expected.RearmSeconds {
+		return true
+	}
+	if (actual.Active == nil || *actual.Active) != (expected.Active == nil || *expected.Active) {
+		return true
+	}
+
+	services := schema.NewSet(schema.HashInt, nil)
+	for _, id := range actual.Services {
+		services.Add(id)
+	}
+	expectedServices := schema.NewSet(schema.HashInt, nil)
+	for _, id := range expected.Services {
+		expectedServices.Add(id)
+	}
+	if !services.Equal(expectedServices) {
+		return true
+	}
+
+	return !reflect.DeepEqual(flattenCondition(nil, actual.Conditions), flattenCondition(nil, expected.Conditions))
+}
+
+func resourceAppOpticsAlertGroupCreateAlert(d *schema.ResourceData, meta interface{}, value string) (int, error) {
+	client := meta.(*providerMeta).client
+	alert := resourceAppOpticsAlertGroupExpand(d, meta, value)
+
+	alertResult, err := client.AlertsService().Create(alert)
+	if err != nil {
+		return 0, fmt.Errorf("Error creating AppOptics alert %s: %s", alert.Name, err)
+	}
+	log.Printf("[INFO] Created AppOptics alert %s for %s", alertResult.Name, value)
+
+	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		_, err := client.AlertsService().Retrieve(alertResult.ID)
+		if err != nil {
+			if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if retryErr != nil {
+		return alertResult.ID, fmt.Errorf("Error creating AppOptics alert %s: %s", alert.Name, retryErr)
+	}
+
+	return alertResult.ID, nil
+}
+
+func resourceAppOpticsAlertGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(resource.UniqueId())
+
+	return resourceAppOpticsAlertGroupUpdate(d, meta)
+}
+
+func resourceAppOpticsAlertGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	alertIDs := make(map[string]interface{})
+	drifted := make([]interface{}, 0)
+	for value, v := range d.Get("alert_ids").(map[string]interface{}) {
+		id, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return err
+		}
+
+		alert, err := client.AlertsService().Retrieve(id)
+		if err != nil {
+			if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+				log.Printf("[WARN] AppOptics alert %d for %s not found, it will be created again", id, value)
+				continue
+			}
+			return fmt.Errorf("Error reading AppOptics Alert %d: %s", id, err)
+		}
+		alertIDs[value] = v
+
+		if alertGroupAlertDrifted(resourceAppOpticsAlertGroupExpand(d, meta, value), alert) {
+			log.Printf("[WARN] AppOptics alert %d for %s was changed outside of Terraform", id, value)
+			drifted = append(drifted, value)
+		}
+	}
+
+	if err := d.Set("drifted_values", schema.NewSet(schema.HashString, drifted)); err != nil {
+		return err
+	}
+	return d.Set("alert_ids", alertIDs)
+}
+
+// Imports a group from the ID <tag_name>:<name_pattern>. Every alert whose
+// name matches the pattern becomes the alert of the value in place of
+// {value}. The template is taken from the alert of the first value, the
+// others show up in drifted_values if they differ from it.
+func resourceAppOpticsAlertGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || !strings.Contains(parts[1], alertGroupValuePlaceholder) {
+		return nil, fmt.Errorf("Error parsing AppOptics alert group ID %s, expected <tag_name>:<name_pattern>, e.g. region:cpu-%s", d.Id(), alertGroupValuePlaceholder)
+	}
+	tagName, namePattern := parts[0], parts[1]
+
+	alerts, err := alertList(meta.(*providerMeta).client)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing AppOptics alerts: %s", err)
+	}
+
+	quoted := regexp.QuoteMeta(meta.(*providerMeta).managedName(namePattern))
+	nameRegexp := regexp.MustCompile("^" + strings.Replace(quoted, regexp.QuoteMeta(alertGroupValuePlaceholder), "(.+)", 1) + "$")
+
+	alertIDs := make(map[string]interface{})
+	values := make([]string, 0)
+	byValue := make(map[string]*appoptics.Alert)
+	for _, alert := range alerts {
+		match := nameRegexp.FindStringSubmatch(alert.Name)
+		if match == nil {
+			continue
+		}
+		if err := meta.(*providerMeta).checkImport("alert", alert.Name, meta.(*providerMeta).isMarked(alert.Description)); err != nil {
+			return nil, err
+		}
+		alertIDs[match[1]] = strconv.Itoa(alert.ID)
+		values = append(values, match[1])
+		byValue[match[1]] = alert
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("No AppOptics alert matches the name pattern %s", namePattern)
+	}
+	sort.Strings(values)
+	template := byValue[values[0]]
+
+	// The group's own filter isn't part of the template
+	for _, condition := range template.Conditions {
+		tags := make([]*appoptics.Tag, 0, len(condition.Tags))
+		for _, tag := range condition.Tags {
+			if tag.Name != tagName {
+				tags = append(tags, tag)
+			}
+		}
+		condition.Tags = tags
+	}
+
+	tagValues := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		tagValues = append(tagValues, value)
+	}
+
+	d.SetId(resource.UniqueId())
+	for k, v := range map[string]interface{}{
+		"name_pattern":  namePattern,
+		"tag_name":      tagName,
+		"tag_values":    schema.NewSet(schema.HashString, tagValues),
+		"alert_ids":     alertIDs,
+		"description":   meta.(*providerMeta).unmark(template.Description),
+		"active":        template.Active == nil || *template.Active,
+		"rearm_seconds": template.RearmSeconds,
+		"services":      schema.NewSet(schema.HashInt, flattenServices(d, template.Services)),
+		"condition":     flattenCondition(d, template.Conditions),
+	} {
+		if err := d.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAppOpticsAlertGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	// alert_ids is unknown in the plan when tag_values changes, the state has
+	// the alerts that exist so far
+	alertIDs := make(map[string]interface{})
+	o, _ := d.GetChange("alert_ids")
+	for value, id := range o.(map[string]interface{}) {
+		alertIDs[value] = id
+	}
+
+	// Alerts are recorded as soon as they exist, so that a failure halfway
+	// doesn't lose track of them
+	err := resourceAppOpticsAlertGroupReconcile(d, meta, alertIDs)
+	if setErr := d.Set("alert_ids", alertIDs); setErr != nil {
+		return setErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return resourceAppOpticsAlertGroupRead(d, meta)
+}
+
+// Creates the alerts of new tag values, deletes the ones of removed values and
+// updates the others when the template changed. alertIDs is kept up to date.
+func resourceAppOpticsAlertGroupReconcile(d *schema.ResourceData, meta interface{}, alertIDs map[string]interface{}) error {
+	client := meta.(*providerMeta).client
+	values := d.Get("tag_values").(*schema.Set)
+
+	for value, v := range alertIDs {
+		if values.Contains(value) {
+			continue
+		}
+		if err := checkUpdateDeletionProtection(d, "alert", strings.Replace(d.Get("name_pattern").(string), alertGroupValuePlaceholder, value, -1), v.(string)); err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return err
+		}
+		if err := resourceAppOpticsAlertGroupDeleteAlert(client, id); err != nil {
+			return err
+		}
+		delete(alertIDs, value)
+	}
+
+	templateChanged := d.HasChanges("name_pattern", "tag_name", "description", "active", "rearm_seconds", "services", "condition")
+	drifted, _ := d.GetChange("drifted_values")
+	for _, v := range values.List() {
+		value := v.(string)
+
+		existing, ok := alertIDs[value]
+		if !ok {
+			id, err := resourceAppOpticsAlertGroupCreateAlert(d, meta, value)
+			if id != 0 {
+				alertIDs[value] = strconv.Itoa(id)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !templateChanged && !drifted.(*schema.Set).Contains(value) {
+			continue
+		}
+		id, err := strconv.Atoi(existing.(string))
+		if err != nil {
+			return err
+		}
+		alert := resourceAppOpticsAlertGroupExpand(d, meta, value)
+		alert.ID = id
+		log.Printf("[INFO] Updating AppOptics alert %s for %s", alert.Name, value)
+		if err := client.AlertsService().Update(alert); err != nil {
+			return fmt.Errorf("Error updating AppOptics alert %s: %s", alert.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceAppOpticsAlertGroupDeleteAlert(client *appoptics.Client, id int) error {
+	log.Printf("[INFO] Deleting Alert: %d", id)
+	if err := client.AlertsService().Delete(id); err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error deleting Alert %d: %s", id, err)
+	}
+
+	return resource.Retry(1*time.Minute, func() *resource.RetryError {
+		_, err := client.AlertsService().Retrieve(id)
+		if err != nil {
+			if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+				return nil
+			}
+			return resource.NonRetryableError(err)
+		}
+		return resource.RetryableError(fmt.Errorf("alert %d still exists", id))
+	})
+}
+
+func resourceAppOpticsAlertGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	if err := checkDeletionProtection(d, "alert group", d.Get("name_pattern").(string)); err != nil {
+		return err
+	}
+
+	for _, v := range d.Get("alert_ids").(map[string]interface{}) {
+		id, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return err
+		}
+		if err := resourceAppOpticsAlertGroupDeleteAlert(client, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package appoptics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccAppOpticsAlertGroupBasic(t *testing.T) {
+	name := acctest.RandString(10)
+	var firstID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsAlertGroupConfig(name, `"us-east-1", "eu-west-1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_alert_group.foobar", "alert_ids.%", "2"),
+					testAccCheckAppOpticsAlertGroupAlertName("appoptics_alert_group.foobar", "us-east-1", name+"-us-east-1"),
+					func(s *terraform.State) error {
+						firstID = s.RootModule().Resources["appoptics_alert_group.foobar"].Primary.Attributes["alert_ids.us-east-1"]
+						return nil
+					},
+				),
+			},
+			// Removing a value only deletes its own alert
+			{
+				Config: testAccCheckAppOpticsAlertGroupConfig(name, `"us-east-1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_alert_group.foobar", "alert_ids.%", "1"),
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr(
+							"appoptics_alert_group.foobar", "alert_ids.us-east-1", firstID)(s)
+					},
+				),
+			},
+			{
+				ResourceName:  "appoptics_alert_group.foobar",
+				ImportState:   true,
+				ImportStateId: "region:" + name + "-{value}",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 state, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					if attributes["alert_ids.us-east-1"] != firstID || attributes["drifted_values.#"] != "0" {
+						return fmt.Errorf("unexpected imported state: %#v", attributes)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAlertGroupTagConditions(t *testing.T) {
+	conditions := []*appoptics.AlertCondition{
+		{
+			MetricName: "system.cpu.utilization",
+			Tags: []*appoptics.Tag{
+				{Name: "region", Grouped: true, Dynamic: true, Values: []string{"*"}},
+				{Name: "environment", Values: []string{"production"}},
+			},
+		},
+		{
+			MetricName: "system.mem.used",
+		},
+	}
+
+	alertGroupTagConditions(conditions, "region", "us-east-1")
+
+	first := conditions[0].Tags
+	if len(first) != 2 || first[0].Name != "region" || first[0].Values[0] != "us-east-1" || !first[0].Grouped || !first[0].Dynamic {
+		t.Errorf("unexpected tags of the first condition: %#v", first)
+	}
+	second := conditions[1].Tags
+	if len(second) != 1 || second[0].Name != "region" || second[0].Values[0] != "us-east-1" {
+		t.Errorf("unexpected tags of the second condition: %#v", second)
+	}
+}
+
+func TestAlertGroupAlertDrifted(t *testing.T) {
+	active := true
+	expected := &appoptics.AlertRequest{
+		Name:         "cpu-us-east-1",
+		Active:       &active,
+		RearmSeconds: 600,
+		Services:     []int{1, 2},
+		Conditions: []*appoptics.AlertCondition{
+			{Type: "above", MetricName: "system.cpu.utilization", Threshold: 10,
+				Tags: []*appoptics.Tag{{Name: "region", Values: []string{"us-east-1"}}}},
+		},
+	}
+	alert := &appoptics.Alert{
+		ID:           1234,
+		Name:         "cpu-us-east-1",
+		RearmSeconds: 600,
+		Services:     []*appoptics.Service{{ID: 2}, {ID: 1}},
+		Conditions: []*appoptics.AlertCondition{
+			{ID: 5, Type: "above", MetricName: "system.cpu.utilization", Threshold: 10,
+				Tags: []*appoptics.Tag{{Name: "region", Values: []string{"us-east-1"}}}},
+		},
+	}
+
+	if alertGroupAlertDrifted(expected, alert) {
+		t.Error("expected an alert matching the template not to drift")
+	}
+
+	alert.Conditions[0].Threshold = 20
+	if !alertGroupAlertDrifted(expected, alert) {
+		t.Error("expected a changed threshold to drift")
+	}
+}
+
+func TestResourceAppOpticsAlertGroupPlanProtectedRemoval(t *testing.T) {
+	state := func(protected bool) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "api",
+			Attributes: map[string]string{
+				"id":           "api",
+				"name_pattern": "api latency {value}",
+				"tag_name":     "service",
+				"tag_values.#": "2",
+				"tag_values." + strconv.Itoa(schema.HashString("web")):    "web",
+				"tag_values." + strconv.Itoa(schema.HashString("worker")): "worker",
+				"alert_ids.%":         "2",
+				"alert_ids.web":       "1",
+				"alert_ids.worker":    "2",
+				"deletion_protection": strconv.FormatBool(protected),
+			},
+		}
+	}
+	config := func(protected bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name_pattern":        "api latency {value}",
+			"tag_name":            "service",
+			"tag_values":          []interface{}{"web"},
+			"deletion_protection": protected,
+		})
+	}
+	r := resourceAppOpticsAlertGroup()
+
+	if _, err := r.Diff(state(true), config(true), &providerMeta{}); err == nil || !strings.Contains(err.Error(), "api latency worker") {
+		t.Errorf("expected the removal of a protected alert to be refused, got %v", err)
+	}
+	// Lifting the protection only counts once applied
+	if _, err := r.Diff(state(true), config(false), &providerMeta{}); err == nil {
+		t.Error("expected the removal to be refused while the state is protected")
+	}
+	if _, err := r.Diff(state(false), config(false), &providerMeta{}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func testAccCheckAppOpticsAlertGroupAlertName(n, value, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		id, err := strconv.Atoi(rs.Primary.Attributes["alert_ids."+value])
+		if err != nil {
+			return fmt.Errorf("No alert for %s", value)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+		alert, err := client.AlertsService().Retrieve(id)
+		if err != nil {
+			return err
+		}
+		if alert.Name != name {
+			return fmt.Errorf("Expected alert %s, found %s", name, alert.Name)
+		}
+		return nil
+	}
+}
+
+func testAccCheckAppOpticsAlertGroupDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "appoptics_alert_group" {
+			continue
+		}
+
+		for k, v := range rs.Primary.Attributes {
+			if k == "alert_ids.%" || !strings.HasPrefix(k, "alert_ids.") {
+				continue
+			}
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("ID not a number")
+			}
+			if _, err := client.AlertsService().Retrieve(id); err == nil {
+				return fmt.Errorf("Alert still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAppOpticsAlertGroupConfig(name, values string) string {
+	return fmt.Sprintf(`
+resource "appoptics_alert_group" "foobar" {
+	name_pattern = "%s-{value}"
+	tag_name     = "region"
+	tag_values   = [%s]
+
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}`, name, values)
+}
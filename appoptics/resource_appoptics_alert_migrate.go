@@ -0,0 +1,188 @@
+package appoptics
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceAppOpticsAlertResourceV0 is the alert schema before conditions
+// became an ordered list. It's a frozen copy, only its shape matters to decode
+// older states.
+func resourceAppOpticsAlertResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"rearm_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  600,
+			},
+			"services": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"condition": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     resourceAppOpticsAlertConditionResourceV0(),
+			},
+			"attributes": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// resourceAppOpticsAlertConditionResourceV0 is the condition block of the V0
+// and V1 alert schemas
+func resourceAppOpticsAlertConditionResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metric_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"grouped": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"dynamic": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"detect_reset": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"duration": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"threshold": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"summary_function": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// Conditions were a set and are now a list. Both are stored as arrays, so the
+// conditions keep the order they had in the state; the next refresh puts them
+// in the order of the API.
+func resourceAppOpticsAlertStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if conditions, ok := rawState["condition"].([]interface{}); ok {
+		log.Printf("[DEBUG] Moving %d conditions of alert %v to a list", len(conditions), rawState["id"])
+	}
+
+	return rawState, nil
+}
+
+// resourceAppOpticsAlertResourceV1 is the alert schema before services became
+// numeric IDs. Like V0, it's a frozen copy.
+func resourceAppOpticsAlertResourceV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"rearm_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  600,
+			},
+			"services": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"condition": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     resourceAppOpticsAlertConditionResourceV0(),
+			},
+			"attributes": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// Services were referenced by their IDs as strings. Empty strings are what
+// the old flattening made of unknown services, they're dropped and left to the
+// next refresh.
+func resourceAppOpticsAlertStateUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	services, ok := rawState["services"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+
+	serviceIDs := make([]interface{}, 0, len(services))
+	for _, v := range services {
+		if v == "" {
+			continue
+		}
+		serviceID, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("service %q of alert %v isn't a numeric ID: %s", v, rawState["id"], err)
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	rawState["services"] = serviceIDs
+
+	return rawState, nil
+}
@@ -0,0 +1,136 @@
+package appoptics
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Runs a flatmap state, as written by the provider before r had state
+// upgraders, through all of them and decodes the result with the current
+// schema of r, the way Terraform upgrades such a state.
+func testUpgradeFlatmapState(t *testing.T, r *schema.Resource, attrs map[string]string) map[string]interface{} {
+	t.Helper()
+
+	ty := r.StateUpgraders[0].Type
+	val, err := (&terraform.InstanceState{ID: attrs["id"], Attributes: attrs}).AttrsAsObjectValue(ty)
+	if err != nil {
+		t.Fatalf("error decoding the flatmap state: %s", err)
+	}
+	rawState, err := schema.StateValueToJSONMap(val, ty)
+	if err != nil {
+		t.Fatalf("error converting the state: %s", err)
+	}
+
+	for _, upgrader := range r.StateUpgraders {
+		if rawState, err = upgrader.Upgrade(rawState, nil); err != nil {
+			t.Fatalf("error upgrading from version %d: %s", upgrader.Version, err)
+		}
+	}
+
+	if _, err := schema.JSONMapToStateValue(rawState, r.CoreConfigSchema()); err != nil {
+		t.Fatalf("the upgraded state doesn't fit the current schema: %s", err)
+	}
+	return rawState
+}
+
+func TestResourceAppOpticsAlertStateUpgradeV0(t *testing.T) {
+	condition := map[string]interface{}{
+		"type":        "above",
+		"metric_name": "system.cpu.utilization",
+		"threshold":   10.0,
+		"tag": []interface{}{
+			map[string]interface{}{"name": "hostname", "dynamic": true},
+		},
+	}
+	rawState := map[string]interface{}{
+		"id":        "1234",
+		"name":      "cpu",
+		"condition": []interface{}{condition},
+	}
+
+	actual, err := resourceAppOpticsAlertStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []interface{}{condition}
+	if !reflect.DeepEqual(actual["condition"], expected) {
+		t.Errorf("unexpected conditions:\n%#v", actual["condition"])
+	}
+}
+
+func TestResourceAppOpticsAlertStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":       "1234",
+		"name":     "cpu",
+		"services": []interface{}{"201647", "201648", ""},
+	}
+
+	actual, err := resourceAppOpticsAlertStateUpgradeV1(rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []interface{}{201647, 201648}
+	if !reflect.DeepEqual(actual["services"], expected) {
+		t.Errorf("unexpected services:\n%#v", actual["services"])
+	}
+
+	rawState["services"] = []interface{}{"email"}
+	if _, err := resourceAppOpticsAlertStateUpgradeV1(rawState, nil); err == nil {
+		t.Error("expected an error for a service that isn't a numeric ID")
+	}
+}
+
+func TestResourceAppOpticsAlertStateUpgradeFromBaseline(t *testing.T) {
+	rawState := testUpgradeFlatmapState(t, resourceAppOpticsAlert(), map[string]string{
+		"id":                                    "1234",
+		"name":                                  "cpu",
+		"description":                           "",
+		"active":                                "true",
+		"rearm_seconds":                         "600",
+		"services.#":                            "2",
+		"services.1543216851":                   "201647",
+		"services.3267315813":                   "201648",
+		"condition.#":                           "1",
+		"condition.2881960442.type":             "above",
+		"condition.2881960442.metric_name":      "system.cpu.utilization",
+		"condition.2881960442.threshold":        "10",
+		"condition.2881960442.duration":         "60",
+		"condition.2881960442.detect_reset":     "false",
+		"condition.2881960442.summary_function": "",
+		"condition.2881960442.tag.#":            "1",
+		"condition.2881960442.tag.0.name":       "hostname",
+		"condition.2881960442.tag.0.grouped":    "false",
+		"condition.2881960442.tag.0.dynamic":    "true",
+		"condition.2881960442.tag.0.values.#":   "0",
+		"attributes.%":                          "1",
+		"attributes.runbook_url":                "https://example.com/cpu",
+	})
+
+	services := make([]int, 0)
+	for _, v := range rawState["services"].([]interface{}) {
+		services = append(services, v.(int))
+	}
+	sort.Ints(services)
+	if !reflect.DeepEqual(services, []int{201647, 201648}) {
+		t.Errorf("unexpected services: %#v", rawState["services"])
+	}
+
+	conditions := rawState["condition"].([]interface{})
+	if len(conditions) != 1 {
+		t.Fatalf("unexpected conditions: %#v", conditions)
+	}
+	condition := conditions[0].(map[string]interface{})
+	if condition["metric_name"] != "system.cpu.utilization" || condition["threshold"] != 10.0 {
+		t.Errorf("unexpected condition: %#v", condition)
+	}
+	tag := condition["tag"].([]interface{})[0].(map[string]interface{})
+	if tag["name"] != "hostname" || tag["dynamic"] != true {
+		t.Errorf("unexpected condition tag: %#v", tag)
+	}
+}
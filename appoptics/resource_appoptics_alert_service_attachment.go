@@ -0,0 +1,185 @@
+package appoptics
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAppOpticsAlertServiceAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAppOpticsAlertServiceAttachmentCreate,
+		Read:   resourceAppOpticsAlertServiceAttachmentRead,
+		Update: resourceAppOpticsAlertServiceAttachmentUpdate,
+		Delete: resourceAppOpticsAlertServiceAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAppOpticsAlertServiceAttachmentImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"alert_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"service_id": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+}
+
+// Attachments are identified as <alert_id>/<service_id>
+func parseAlertServiceAttachmentID(id string) (int, int, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected an ID of the form <alert_id>/<service_id>, got %q", id)
+	}
+	alertID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("alert ID %q isn't a number", parts[0])
+	}
+	serviceID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("service ID %q isn't a number", parts[1])
+	}
+	return alertID, serviceID, nil
+}
+
+func resourceAppOpticsAlertServiceAttachmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	alertID, serviceID, err := parseAlertServiceAttachmentID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("alert_id", alertID)          //nolint
+	d.Set("service_id", serviceID)      //nolint
+	d.Set("deletion_protection", false) //nolint
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns whether the service is one of the alert's. A deleted alert has no
+// services.
+func alertHasService(client *appoptics.Client, alertID, serviceID int) (bool, error) {
+	alert, err := client.AlertsService().Retrieve(alertID)
+	if err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, service := range alert.Services {
+		if service.ID == serviceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func resourceAppOpticsAlertServiceAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	alertID := d.Get("alert_id").(int)
+	serviceID := d.Get("service_id").(int)
+
+	log.Printf("[INFO] Attaching service %d to alert %d", serviceID, alertID)
+	if err := client.AlertsService().AssociateToService(alertID, serviceID); err != nil {
+		return fmt.Errorf("Error attaching service %d to AppOptics alert %d: %s", serviceID, alertID, err)
+	}
+	d.SetId(fmt.Sprintf("%d/%d", alertID, serviceID))
+
+	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		attached, err := alertHasService(client, alertID, serviceID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if !attached {
+			return resource.RetryableError(fmt.Errorf("service %d not attached to alert %d yet", serviceID, alertID))
+		}
+		return nil
+	})
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return resourceAppOpticsAlertServiceAttachmentRead(d, meta)
+}
+
+func resourceAppOpticsAlertServiceAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	alertID, serviceID, err := parseAlertServiceAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	attached, err := alertHasService(client, alertID, serviceID)
+	if err != nil {
+		return fmt.Errorf("Error reading AppOptics Alert %d: %s", alertID, err)
+	}
+	if !attached {
+		log.Printf("[WARN] Service %d no longer attached to alert %d, removing from state", serviceID, alertID)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("alert_id", alertID); err != nil {
+		return err
+	}
+	return d.Set("service_id", serviceID)
+}
+
+// Only deletion_protection changes in place, which doesn't touch the API
+func resourceAppOpticsAlertServiceAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceAppOpticsAlertServiceAttachmentRead(d, meta)
+}
+
+func resourceAppOpticsAlertServiceAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	alertID, serviceID, err := parseAlertServiceAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if err := checkDeletionProtection(d, "alert service attachment", fmt.Sprintf("service %d of alert %d", serviceID, alertID)); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Detaching service %d from alert %d", serviceID, alertID)
+	if err := client.AlertsService().DisassociateFromService(alertID, serviceID); err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error detaching service %d from AppOptics alert %d: %s", serviceID, alertID, err)
+	}
+
+	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		attached, err := alertHasService(client, alertID, serviceID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if attached {
+			return resource.RetryableError(fmt.Errorf("service %d still attached to alert %d", serviceID, alertID))
+		}
+		return nil
+	})
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package appoptics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccAppOpticsAlertServiceAttachmentBasic(t *testing.T) {
+	var alert appoptics.Alert
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsAlertServiceAttachmentConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsAlertExists("appoptics_alert.foobar", &alert),
+					testAccCheckAppOpticsAlertServiceCount(&alert, 1),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "services.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "appoptics_alert_service_attachment.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseAlertServiceAttachmentID(t *testing.T) {
+	alertID, serviceID, err := parseAlertServiceAttachmentID("123/456")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if alertID != 123 || serviceID != 456 {
+		t.Errorf("unexpected IDs: %d, %d", alertID, serviceID)
+	}
+
+	for _, id := range []string{"123", "123/456/789", "abc/456", "123/"} {
+		if _, _, err := parseAlertServiceAttachmentID(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
+
+func testAccCheckAppOpticsAlertServiceCount(alert *appoptics.Alert, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(alert.Services) != count {
+			return fmt.Errorf("Expected %d services, found %d", count, len(alert.Services))
+		}
+		return nil
+	}
+}
+
+func testAccCheckAppOpticsAlertServiceAttachmentConfig(name string) string {
+	return fmt.Sprintf(`
+resource "appoptics_notification_service" "foobar" {
+    title = "Foo Bar"
+    type = "mail"
+    settings = <<EOF
+{
+  "addresses": "admin@example.com"
+}
+EOF
+}
+
+resource "appoptics_alert" "foobar" {
+	name = "%s"
+	manage_services = false
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}
+
+resource "appoptics_alert_service_attachment" "foobar" {
+	alert_id   = appoptics_alert.foobar.id
+	service_id = appoptics_notification_service.foobar.id
+}`, name)
+}
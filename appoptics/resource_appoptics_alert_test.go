@@ -2,6 +2,7 @@ package appoptics
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -77,23 +78,23 @@ func TestAccAppOpticsAlertFull(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"appoptics_alert.foobar", "attributes.runbook_url", runbookUrl),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.metric_name", "system.cpu.utilization"),
+						"appoptics_alert.foobar", "condition.0.metric_name", "system.cpu.utilization"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.summary_function", ""),
+						"appoptics_alert.foobar", "condition.0.summary_function", ""),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.threshold", "10"),
+						"appoptics_alert.foobar", "condition.0.threshold", "10"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.type", "above"),
+						"appoptics_alert.foobar", "condition.0.type", "above"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.tag.0.grouped", "true"),
+						"appoptics_alert.foobar", "condition.0.tag.0.grouped", "true"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.tag.0.name", "hostname"),
+						"appoptics_alert.foobar", "condition.0.tag.0.name", "hostname"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.tag.0.values.#", "2"),
+						"appoptics_alert.foobar", "condition.0.tag.0.values.#", "2"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.tag.0.values.0", "host1"),
+						"appoptics_alert.foobar", "condition.0.tag.0.values.0", "host1"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.411654007.tag.0.values.1", "host2"),
+						"appoptics_alert.foobar", "condition.0.tag.0.values.1", "host2"),
 					resource.TestCheckResourceAttr(
 						"appoptics_alert.foobar", "rearm_seconds", "300"),
 				),
@@ -186,6 +187,97 @@ func TestAccAppOpticsAlertManageRunbookUrl(t *testing.T) {
 	})
 }
 
+func TestAccAppOpticsAlertRunbookURL(t *testing.T) {
+	var alert appoptics.Alert
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsAlertConfigRunbookURL(name, runbookUrl),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsAlertExists("appoptics_alert.foobar", &alert),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "runbook_url", runbookUrl),
+				),
+			},
+			// Removing runbook_url clears it
+			{
+				Config: testAccCheckAppOpticsAlertConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsAlertExists("appoptics_alert.foobar", &alert),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "runbook_url", ""),
+				),
+			},
+			{
+				Config:      testAccCheckAppOpticsAlertConfigRunbookURL(name, "not a URL"),
+				ExpectError: regexp.MustCompile(`expected "runbook_url" to have a host`),
+			},
+		},
+	})
+}
+
+func TestAccAppOpticsAlertClearOnUpdate(t *testing.T) {
+	var alert appoptics.Alert
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsAlertConfigClearOnUpdate(name, 10),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsAlertExists("appoptics_alert.foobar", &alert),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "clear_on_update", "true"),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "last_cleared_at", ""),
+				),
+			},
+			{
+				Config: testAccCheckAppOpticsAlertConfigClearOnUpdate(name, 20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsAlertExists("appoptics_alert.foobar", &alert),
+					resource.TestCheckResourceAttr(
+						"appoptics_alert.foobar", "condition.0.threshold", "20"),
+					resource.TestMatchResourceAttr(
+						"appoptics_alert.foobar", "last_cleared_at", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAlertConditionsApplied(t *testing.T) {
+	sent := []*appoptics.AlertCondition{
+		{Type: "above", MetricName: "cpu", Threshold: 90, Tags: []*appoptics.Tag{{Name: "env", Values: []string{"a", "b"}}}},
+		{Type: "absent", MetricName: "mem", Duration: 300},
+	}
+
+	// Fields the API fills in, reordered conditions and tag values match
+	current := []*appoptics.AlertCondition{
+		{ID: 2, Type: "absent", MetricName: "mem", Duration: 300, SummaryFunction: "average"},
+		{ID: 1, Type: "above", MetricName: "cpu", Threshold: 90, SummaryFunction: "average", Tags: []*appoptics.Tag{{Name: "env", Values: []string{"b", "a"}}}},
+	}
+	if !alertConditionsApplied(sent, current) {
+		t.Error("expected the sent conditions to be applied")
+	}
+
+	current[1].Threshold = 80
+	if alertConditionsApplied(sent, current) {
+		t.Error("expected the old threshold to count as not applied yet")
+	}
+	if alertConditionsApplied(sent, current[:1]) {
+		t.Error("expected a missing condition to count as not applied yet")
+	}
+}
+
 func TestAccAppOpticsAlertManageAttributeActive(t *testing.T) {
 	var alert appoptics.Alert
 	name := acctest.RandString(10)
@@ -278,11 +370,11 @@ func TestAccAppOpticsAlertFullUpdate(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"appoptics_alert.foobar", "rearm_seconds", "1200"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.498665064.metric_name", "system.cpu.utilization"),
+						"appoptics_alert.foobar", "condition.0.metric_name", "system.cpu.utilization"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.498665064.type", "above"),
+						"appoptics_alert.foobar", "condition.0.type", "above"),
 					resource.TestCheckResourceAttr(
-						"appoptics_alert.foobar", "condition.498665064.threshold", "10"),
+						"appoptics_alert.foobar", "condition.0.threshold", "10"),
 				),
 			},
 		},
@@ -290,7 +382,7 @@ func TestAccAppOpticsAlertFullUpdate(t *testing.T) {
 }
 
 func testAccCheckAppOpticsAlertDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*appoptics.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "appoptics_alert" {
@@ -346,7 +438,7 @@ func testAccCheckAppOpticsAlertExists(n string, alert *appoptics.Alert) resource
 			return fmt.Errorf("No Alert ID is set")
 		}
 
-		client := testAccProvider.Meta().(*appoptics.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		id, err := strconv.ParseUint(rs.Primary.ID, 10, 0)
 		if err != nil {
@@ -424,6 +516,33 @@ resource "appoptics_alert" "foobar" {
 }`, name, runbook)
 }
 
+func testAccCheckAppOpticsAlertConfigRunbookURL(name string, runbook string) string {
+	return fmt.Sprintf(`
+resource "appoptics_alert" "foobar" {
+    name = "%s"
+	description = "A Test Alert"
+	runbook_url = "%s"
+	condition {
+		type        = "above"
+		threshold   = 10
+		metric_name = "system.cpu.utilization"
+	}
+}`, name, runbook)
+}
+
+func testAccCheckAppOpticsAlertConfigClearOnUpdate(name string, threshold int) string {
+	return fmt.Sprintf(`
+resource "appoptics_alert" "foobar" {
+	name = "%s"
+	clear_on_update = true
+	condition {
+		type        = "above"
+		threshold   = %d
+		metric_name = "system.cpu.utilization"
+	}
+}`, name, threshold)
+}
+
 func testAccCheckAppOpticsAlertConfigNewValue(name string) string {
 	return fmt.Sprintf(`
 resource "appoptics_alert" "foobar" {
@@ -452,7 +571,7 @@ EOF
 resource "appoptics_alert" "foobar" {
 	name = "%s"
 	description = "A Test Alert"
-	services = [ "${appoptics_notification_service.foobar.id}" ]
+	services = [ appoptics_notification_service.foobar.id ]
 	condition {
 		type        = "above"
 		threshold   = 10
@@ -486,7 +605,7 @@ EOF
 resource "appoptics_alert" "foobar" {
     name = "%s"
 	description = "A Test Alert"
-	services = [ "${appoptics_notification_service.foobar.id}" ]
+	services = [ appoptics_notification_service.foobar.id ]
 	condition {
 		type        = "above"
 		threshold   = 10
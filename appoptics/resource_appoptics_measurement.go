@@ -0,0 +1,126 @@
+package appoptics
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// appoptics_measurement submits a single measurement, e.g. to smoke test
+// alerts and their notification services. Measurements can't be read back or
+// deleted, so the resource only tracks when it was last submitted.
+func resourceAppOpticsMeasurement() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAppOpticsMeasurementCreate,
+		Read:   resourceAppOpticsMeasurementRead,
+		Update: resourceAppOpticsMeasurementUpdate,
+		Delete: resourceAppOpticsMeasurementDelete,
+
+		CustomizeDiff: resourceAppOpticsMeasurementCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"value": {
+				Type:     schema.TypeFloat,
+				Required: true,
+			},
+			"time": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"keepalive": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"submitted_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Every submission changes submitted_at, with keepalive that's every apply
+func resourceAppOpticsMeasurementCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.Get("keepalive").(bool) || d.HasChange("name") || d.HasChange("tags") || d.HasChange("value") || d.HasChange("time") {
+		return d.SetNewComputed("submitted_at")
+	}
+	return nil
+}
+
+func resourceAppOpticsMeasurementSubmit(d *schema.ResourceData, client *appoptics.Client) error {
+	now := time.Now()
+
+	measurement := appoptics.Measurement{
+		Name:  d.Get("name").(string),
+		Value: d.Get("value").(float64),
+		Time:  now.Unix(),
+	}
+	if v, ok := d.GetOk("time"); ok {
+		measurement.Time = int64(v.(int))
+	}
+	if v, ok := d.GetOk("tags"); ok {
+		measurement.Tags = make(map[string]string)
+		for k, tag := range v.(map[string]interface{}) {
+			measurement.Tags[k] = tag.(string)
+		}
+	}
+
+	log.Printf("[INFO] Submitting AppOptics measurement of %s: %v", measurement.Name, measurement.Value)
+	if _, err := client.MeasurementsService().Create(&appoptics.MeasurementsBatch{
+		Measurements: []appoptics.Measurement{measurement},
+	}); err != nil {
+		return fmt.Errorf("Error submitting AppOptics measurement of %s: %s", measurement.Name, err)
+	}
+
+	return d.Set("submitted_at", now.UTC().Format(time.RFC3339))
+}
+
+func resourceAppOpticsMeasurementCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	if err := resourceAppOpticsMeasurementSubmit(d, client); err != nil {
+		return err
+	}
+	d.SetId(resource.UniqueId())
+
+	return resourceAppOpticsMeasurementRead(d, meta)
+}
+
+// There's nothing to refresh, measurements are write-only
+func resourceAppOpticsMeasurementRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceAppOpticsMeasurementUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	if err := resourceAppOpticsMeasurementSubmit(d, client); err != nil {
+		return err
+	}
+
+	return resourceAppOpticsMeasurementRead(d, meta)
+}
+
+func resourceAppOpticsMeasurementDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Removing AppOptics measurement of %s from state, submitted measurements are kept", d.Get("name").(string))
+	return nil
+}
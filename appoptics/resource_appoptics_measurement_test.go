@@ -0,0 +1,68 @@
+package appoptics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccAppOpticsMeasurementBasic(t *testing.T) {
+	name := fmt.Sprintf("tftest.measurement.%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsMeasurementConfig(name, 1, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_measurement.foobar", "value", "1"),
+					resource.TestCheckResourceAttrSet(
+						"appoptics_measurement.foobar", "submitted_at"),
+				),
+			},
+			{
+				Config: testAccCheckAppOpticsMeasurementConfig(name, 2, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_measurement.foobar", "value", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAppOpticsMeasurementKeepalive(t *testing.T) {
+	name := fmt.Sprintf("tftest.measurement.%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsMeasurementConfig(name, 1, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"appoptics_measurement.foobar", "submitted_at"),
+				),
+				// Keepalive submits again on every apply
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCheckAppOpticsMeasurementConfig(name string, value int, keepalive bool) string {
+	return fmt.Sprintf(`
+resource "appoptics_measurement" "foobar" {
+	name      = "%s"
+	value     = %d
+	keepalive = %t
+	tags = {
+		environment = "test"
+	}
+}`, name, value, keepalive)
+}
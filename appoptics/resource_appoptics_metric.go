@@ -48,58 +48,63 @@ func resourceAppOpticsMetric() *schema.Resource {
 				Type:     schema.TypeList,
 				Optional: true,
 				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"color": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"summarize_function": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"display_max": {
-							Type:     schema.TypeFloat,
-							Optional: true,
-						},
-						"display_min": {
-							Type:     schema.TypeFloat,
-							Optional: true,
-						},
-						"display_units_long": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"display_units_short": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"display_stacked": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
-						},
-						"created_by_ua": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"gap_detection": {
-							Type:     schema.TypeBool,
-							Optional: true,
-						},
-						"aggregate": {
-							Type:     schema.TypeBool,
-							Optional: true,
-						},
-					},
-				},
+				Elem:     resourceAppOpticsMetricAttributesResource(),
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+}
+
+func resourceAppOpticsMetricAttributesResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"color": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"summarize_function": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"display_max": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"display_min": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"display_units_long": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"display_units_short": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"display_stacked": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"created_by_ua": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"gap_detection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"aggregate": {
+				Type:     schema.TypeBool,
+				Optional: true,
 			},
 		},
 	}
 }
 
 func resourceAppOpticsMetricCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	metric := appoptics.Metric{
 		Name: d.Get("name").(string),
 		Type: "gauge",
@@ -119,43 +124,7 @@ func resourceAppOpticsMetricCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	if a, ok := d.GetOk("attributes"); ok {
-
-		attributeData := a.([]interface{})
-		attributeDataMap := attributeData[0].(map[string]interface{})
-		attributes := appoptics.MetricAttributes{}
-
-		if v, ok := attributeDataMap["color"].(string); ok && v != "" {
-			attributes.Color = v
-		}
-		if v, ok := attributeDataMap["display_max"].(float64); ok && v != 0.0 {
-			attributes.DisplayMax = v
-		}
-		if v, ok := attributeDataMap["display_min"].(float64); ok && v != 0.0 {
-			attributes.DisplayMin = v
-		}
-		if v, ok := attributeDataMap["display_units_long"].(string); ok && v != "" {
-			attributes.DisplayUnitsLong = v
-		}
-		if v, ok := attributeDataMap["display_units_short"].(string); ok && v != "" {
-			attributes.DisplayUnitsShort = v
-		}
-		if v, ok := attributeDataMap["created_by_ua"].(string); ok && v != "" {
-			attributes.CreatedByUA = v
-		}
-		if v, ok := attributeDataMap["summarize_function"].(string); ok && v != "" {
-			attributes.SummarizeFunction = v
-		}
-		if v, ok := attributeDataMap["display_stacked"].(bool); ok {
-			attributes.DisplayStacked = v
-		}
-		if v, ok := attributeDataMap["gap_detection"].(bool); ok {
-			attributes.GapDetection = v
-		}
-		if v, ok := attributeDataMap["aggregate"].(bool); ok {
-			attributes.Aggregate = v
-		}
-
-		metric.Attributes = attributes
+		metric.Attributes = resourceAppOpticsMetricAttributesExpand(a.([]interface{}), resourceDataSetFunc(d, "attributes.0."))
 	}
 
 	_, err := client.MetricsService().Create(&metric)
@@ -163,6 +132,9 @@ func resourceAppOpticsMetricCreate(d *schema.ResourceData, meta interface{}) err
 		log.Printf("[INFO] ERROR creating Metric: %s", err)
 		return fmt.Errorf("error creating AppOptics metric: %s", err)
 	}
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		cache.invalidate(metric.Name)
+	}
 
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := client.MetricsService().Retrieve(metric.Name)
@@ -183,12 +155,18 @@ func resourceAppOpticsMetricCreate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceAppOpticsMetricRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 
 	log.Printf("[INFO] Reading AppOptics Metric: %s", id)
-	metric, err := client.MetricsService().Retrieve(id)
+	var metric *appoptics.Metric
+	var err error
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		metric, err = cache.retrieve(client, id)
+	} else {
+		metric, err = client.MetricsService().Retrieve(id)
+	}
 	if err != nil {
 		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
 			d.SetId("")
@@ -228,7 +206,7 @@ func resourceAppOpticsMetricRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceAppOpticsMetricUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 	metric, err := client.MetricsService().Retrieve(id)
@@ -253,41 +231,7 @@ func resourceAppOpticsMetricUpdate(d *schema.ResourceData, meta interface{}) err
 		metric.Composite = d.Get("composite").(string)
 	}
 	if d.HasChange("attributes") {
-		attributeData := d.Get("attributes").([]interface{})
-		attributeDataMap := attributeData[0].(map[string]interface{})
-		attributes := appoptics.MetricAttributes{}
-
-		if v, ok := attributeDataMap["color"].(string); ok && v != "" {
-			attributes.Color = v
-		}
-		if v, ok := attributeDataMap["display_max"].(float64); ok && v != 0.0 {
-			attributes.DisplayMax = v
-		}
-		if v, ok := attributeDataMap["display_min"].(float64); ok && v != 0.0 {
-			attributes.DisplayMin = v
-		}
-		if v, ok := attributeDataMap["display_units_long"].(string); ok && v != "" {
-			attributes.DisplayUnitsLong = v
-		}
-		if v, ok := attributeDataMap["summarize_function"].(string); ok && v != "" {
-			attributes.SummarizeFunction = v
-		}
-		if v, ok := attributeDataMap["display_units_short"].(string); ok && v != "" {
-			attributes.DisplayUnitsShort = v
-		}
-		if v, ok := attributeDataMap["created_by_ua"].(string); ok && v != "" {
-			attributes.CreatedByUA = v
-		}
-		if v, ok := attributeDataMap["display_stacked"].(bool); ok {
-			attributes.DisplayStacked = v
-		}
-		if v, ok := attributeDataMap["gap_detection"].(bool); ok {
-			attributes.GapDetection = v
-		}
-		if v, ok := attributeDataMap["aggregate"].(bool); ok {
-			attributes.Aggregate = v
-		}
-		metric.Attributes = attributes
+		metric.Attributes = resourceAppOpticsMetricAttributesExpand(d.Get("attributes").([]interface{}), resourceDataSetFunc(d, "attributes.0."))
 	}
 
 	log.Printf("[INFO] Updating AppOptics metric: %v", structToString(metric))
@@ -296,6 +240,9 @@ func resourceAppOpticsMetricUpdate(d *schema.ResourceData, meta interface{}) err
 	if err != nil {
 		return fmt.Errorf("Error updating AppOptics metric: %s", err)
 	}
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		cache.invalidate(id)
+	}
 
 	log.Printf("[INFO] Updated AppOptics metric %s", id)
 
@@ -326,15 +273,25 @@ func resourceAppOpticsMetricUpdate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceAppOpticsMetricDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	id := d.Id()
 
+	if err := checkDeletionProtection(d, "metric", id); err != nil {
+		return err
+	}
+	if err := checkMetricDataProtection(meta, id); err != nil {
+		return err
+	}
+
 	log.Printf("[INFO] Deleting Metric: %s", id)
 	err := client.MetricsService().Delete(id)
 	if err != nil {
 		return fmt.Errorf("Error deleting Metric: %s", err)
 	}
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		cache.invalidate(id)
+	}
 
 	log.Printf("[INFO] Verifying Metric %s deleted", id)
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
@@ -360,6 +317,49 @@ func resourceAppOpticsMetricDelete(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
+// Expands the attributes block. isSet tells explicit zeros apart, paths are
+// relative to the block.
+func resourceAppOpticsMetricAttributesExpand(attributeData []interface{}, isSet attributeSetFunc) appoptics.MetricAttributes {
+	attributes := appoptics.MetricAttributes{}
+	if len(attributeData) == 0 || attributeData[0] == nil {
+		return attributes
+	}
+	attributeDataMap := attributeData[0].(map[string]interface{})
+
+	if v, ok := attributeDataMap["color"].(string); ok && v != "" {
+		attributes.Color = v
+	}
+	if v := expandOptionalFloat(attributeDataMap["display_max"], "display_max", isSet); v != nil {
+		attributes.DisplayMax = *v
+	}
+	if v := expandOptionalFloat(attributeDataMap["display_min"], "display_min", isSet); v != nil {
+		attributes.DisplayMin = *v
+	}
+	if v, ok := attributeDataMap["display_units_long"].(string); ok && v != "" {
+		attributes.DisplayUnitsLong = v
+	}
+	if v, ok := attributeDataMap["display_units_short"].(string); ok && v != "" {
+		attributes.DisplayUnitsShort = v
+	}
+	if v, ok := attributeDataMap["created_by_ua"].(string); ok && v != "" {
+		attributes.CreatedByUA = v
+	}
+	if v, ok := attributeDataMap["summarize_function"].(string); ok && v != "" {
+		attributes.SummarizeFunction = v
+	}
+	if v, ok := attributeDataMap["display_stacked"].(bool); ok {
+		attributes.DisplayStacked = v
+	}
+	if v, ok := attributeDataMap["gap_detection"].(bool); ok {
+		attributes.GapDetection = v
+	}
+	if v, ok := attributeDataMap["aggregate"].(bool); ok {
+		attributes.Aggregate = v
+	}
+
+	return attributes
+}
+
 // Flattens an attributes hash into something that flatmap.Flatten() can handle
 func metricAttributesGather(d *schema.ResourceData, attributes *appoptics.MetricAttributes) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, 1)
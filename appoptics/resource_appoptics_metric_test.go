@@ -2,6 +2,7 @@ package appoptics
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -84,8 +85,41 @@ func TestAccAppOpticsMetrics(t *testing.T) {
 	})
 }
 
+func TestAccAppOpticsMetricDeletionProtection(t *testing.T) {
+	var metric appoptics.Metric
+	name := fmt.Sprintf("tftest-metric-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsMetricDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: protectedMetricConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsMetricExists("appoptics_metric.foobar", &metric),
+					resource.TestCheckResourceAttr(
+						"appoptics_metric.foobar", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      protectedMetricConfig(name, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`is protected from deletion`),
+			},
+			{
+				Config: protectedMetricConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_metric.foobar", "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckAppOpticsMetricDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*appoptics.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "appoptics_metric" {
@@ -143,7 +177,7 @@ func testAccCheckAppOpticsMetricExists(n string, metric *appoptics.Metric) resou
 			return fmt.Errorf("No Metric ID is set")
 		}
 
-		client := testAccProvider.Meta().(*appoptics.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		foundMetric, err := client.MetricsService().Retrieve(rs.Primary.ID)
 
@@ -185,3 +219,15 @@ func compositeMetricConfig(name, typ, desc string) string {
         }
     }`, name, desc))
 }
+
+func protectedMetricConfig(name string, protected bool) string {
+	return strings.TrimSpace(fmt.Sprintf(`
+    resource "appoptics_metric" "foobar" {
+        type = "gauge"
+        name = "%s"
+        deletion_protection = %t
+        attributes {
+          display_stacked = true
+        }
+    }`, name, protected))
+}
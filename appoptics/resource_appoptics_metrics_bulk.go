@@ -0,0 +1,435 @@
+package appoptics
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// appoptics_metrics_bulk manages many gauge definitions at once. Metrics
+// sharing the same definition are created or updated with a single batch
+// request, and new metrics are waited for all at once.
+//
+// The definitions are a set of blocks rather than a map of name to attributes,
+// which the SDK can't express. A changed definition therefore shows up in the
+// plan as a removed and an added block, but they are matched by name and the
+// metric is updated in place.
+func resourceAppOpticsMetricsBulk() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAppOpticsMetricsBulkCreate,
+		Read:   resourceAppOpticsMetricsBulkRead,
+		Update: resourceAppOpticsMetricsBulkUpdate,
+		Delete: resourceAppOpticsMetricsBulkDelete,
+
+		CustomizeDiff: resourceAppOpticsMetricsBulkCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"metric": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"period": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"attributes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem:     resourceAppOpticsMetricAttributesResource(),
+						},
+					},
+				},
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+}
+
+// metricsBatchUpdate is the body of the batch metric update API, applying the
+// same definition to all named metrics. With a type, missing metrics are
+// created.
+type metricsBatchUpdate struct {
+	Names       []string                   `json:"names"`
+	Type        string                     `json:"type,omitempty"`
+	DisplayName string                     `json:"display_name,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Period      int                        `json:"period,omitempty"`
+	Attributes  appoptics.MetricAttributes `json:"attributes"`
+}
+
+// metricsBatchDelete is the body of the batch metric delete API
+type metricsBatchDelete struct {
+	Names []string `json:"names"`
+}
+
+func resourceAppOpticsMetricsBulkCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	names := make(map[string]bool)
+	for _, m := range d.Get("metric").(*schema.Set).List() {
+		name := m.(map[string]interface{})["name"].(string)
+		if name == "" {
+			// Unknown until apply
+			continue
+		}
+		if names[name] {
+			return fmt.Errorf("metric %s is defined more than once", name)
+		}
+		names[name] = true
+	}
+
+	return resourceAppOpticsMetricsBulkCheckRemoved(d, meta, names)
+}
+
+// Refuses to plan the removal of protected metrics. When a new name is unknown
+// until apply, it may still be one of the old ones, and the check is left to
+// the update.
+func resourceAppOpticsMetricsBulkCheckRemoved(d *schema.ResourceDiff, meta interface{}, names map[string]bool) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, m := range d.Get("metric").(*schema.Set).List() {
+		if m.(map[string]interface{})["name"].(string) == "" {
+			return nil
+		}
+	}
+
+	o, _ := d.GetChange("metric")
+	removed := make([]string, 0)
+	for name := range resourceAppOpticsMetricsBulkExpand(nil, o.(*schema.Set)) {
+		if !names[name] {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	sort.Strings(removed)
+	if err := checkDiffDeletionProtection(d, "metrics", strings.Join(removed, ", "), d.Id()); err != nil {
+		return err
+	}
+	for _, name := range removed {
+		if err := checkMetricDataProtection(meta, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the metrics of the set by their name. Explicit zeros are looked up
+// in d when given, otherwise zero counts as unset.
+func resourceAppOpticsMetricsBulkExpand(d *schema.ResourceData, metrics *schema.Set) map[string]*appoptics.Metric {
+	result := make(map[string]*appoptics.Metric, metrics.Len())
+	for _, m := range metrics.List() {
+		data := m.(map[string]interface{})
+		var isSet attributeSetFunc
+		if d != nil {
+			isSet = resourceDataSetFunc(d, fmt.Sprintf("metric.%d.attributes.0.", metrics.F(m)))
+		}
+		metric := &appoptics.Metric{
+			Name:        data["name"].(string),
+			Type:        "gauge",
+			DisplayName: data["display_name"].(string),
+			Description: data["description"].(string),
+			Period:      data["period"].(int),
+			Attributes:  resourceAppOpticsMetricAttributesExpand(data["attributes"].([]interface{}), isSet),
+		}
+		result[metric.Name] = metric
+	}
+	return result
+}
+
+// Groups the metrics by identical definitions, so that each group takes a
+// single batch update. Groups and names are sorted to keep requests stable.
+func metricsBulkGroups(metrics map[string]*appoptics.Metric) []*metricsBatchUpdate {
+	groups := make(map[string]*metricsBatchUpdate)
+	for _, metric := range metrics {
+		update := &metricsBatchUpdate{
+			Type:        metric.Type,
+			DisplayName: metric.DisplayName,
+			Description: metric.Description,
+			Period:      metric.Period,
+			Attributes:  metric.Attributes,
+		}
+		key := structToString(update)
+		if group, ok := groups[key]; ok {
+			group.Names = append(group.Names, metric.Name)
+			continue
+		}
+		update.Names = []string{metric.Name}
+		groups[key] = update
+	}
+
+	result := make([]*metricsBatchUpdate, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.Names)
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Names[0] < result[j].Names[0]
+	})
+	return result
+}
+
+func resourceAppOpticsMetricsBulkCreate(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(resource.UniqueId())
+
+	return resourceAppOpticsMetricsBulkUpdate(d, meta)
+}
+
+func resourceAppOpticsMetricsBulkRead(d *schema.ResourceData, meta interface{}) error {
+	names := make([]string, 0)
+	for _, m := range d.Get("metric").(*schema.Set).List() {
+		names = append(names, m.(map[string]interface{})["name"].(string))
+	}
+	existing, err := metricsRetrieve(meta.(*providerMeta), names)
+	if err != nil {
+		return err
+	}
+
+	metrics := make([]interface{}, 0)
+	for _, m := range d.Get("metric").(*schema.Set).List() {
+		data := m.(map[string]interface{})
+		name := data["name"].(string)
+
+		metric := existing[name]
+		if metric == nil {
+			log.Printf("[WARN] AppOptics metric %s not found, it will be created again", name)
+			continue
+		}
+
+		// Attributes are only compared when they are managed, the API always
+		// has some
+		withAttributes := len(data["attributes"].([]interface{})) > 0
+		metrics = append(metrics, metricsBulkFlatten(d, metric, withAttributes))
+	}
+
+	return d.Set("metric", metrics)
+}
+
+func metricsBulkFlatten(d *schema.ResourceData, metric *appoptics.Metric, withAttributes bool) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":         metric.Name,
+		"display_name": metric.DisplayName,
+		"description":  metric.Description,
+		"period":       metric.Period,
+	}
+	if withAttributes {
+		attributes := make([]interface{}, 0, 1)
+		for _, a := range metricAttributesGather(d, &metric.Attributes) {
+			attributes = append(attributes, a)
+		}
+		result["attributes"] = attributes
+	}
+	return result
+}
+
+func resourceAppOpticsMetricsBulkUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	o, n := d.GetChange("metric")
+	oldMetrics := resourceAppOpticsMetricsBulkExpand(nil, o.(*schema.Set))
+	newMetrics := resourceAppOpticsMetricsBulkExpand(d, n.(*schema.Set))
+
+	// The state can't tell explicit zeros apart, so both sides are compared
+	// without them
+	newCompared := resourceAppOpticsMetricsBulkExpand(nil, n.(*schema.Set))
+
+	removed := make([]string, 0)
+	for name := range oldMetrics {
+		if _, ok := newMetrics[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	// Only new metrics are sent with their type, which makes the API create
+	// them. Existing metrics keep theirs.
+	changed := make(map[string]*appoptics.Metric)
+	added := make(map[string]*appoptics.Metric)
+	for name, metric := range newMetrics {
+		old, ok := oldMetrics[name]
+		if !ok {
+			added[name] = metric
+		} else if structToString(old) != structToString(newCompared[name]) {
+			metric.Type = ""
+			changed[name] = metric
+		}
+	}
+
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		if err := checkUpdateDeletionProtection(d, "metrics", strings.Join(removed, ", "), d.Id()); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			if err := checkMetricDataProtection(meta, name); err != nil {
+				d.Partial(true)
+				return err
+			}
+		}
+	}
+	if err := resourceAppOpticsMetricsBulkDeleteNames(meta, removed); err != nil {
+		return err
+	}
+
+	// Added metrics may already exist, e.g. because measurements were
+	// submitted, in which case they are only updated
+	var created []string
+	if len(added) > 0 {
+		names := make([]string, 0, len(added))
+		for name := range added {
+			names = append(names, name)
+		}
+		existing, err := metricsRetrieve(meta.(*providerMeta), names)
+		if err != nil {
+			return err
+		}
+		for name, metric := range added {
+			if _, ok := existing[name]; ok {
+				metric.Type = ""
+			} else {
+				created = append(created, name)
+			}
+			changed[name] = metric
+		}
+	}
+
+	for _, group := range metricsBulkGroups(changed) {
+		log.Printf("[INFO] Creating or updating AppOptics metrics %v", group.Names)
+		req, err := client.NewRequest("PUT", "metrics", group)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Do(req, nil); err != nil {
+			return fmt.Errorf("Error updating AppOptics metrics %v: %s", group.Names, err)
+		}
+	}
+
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		for name := range newMetrics {
+			cache.invalidate(name)
+		}
+	}
+
+	if len(created) > 0 {
+		if err := resourceAppOpticsMetricsBulkWaitForCreation(meta.(*providerMeta), created); err != nil {
+			return err
+		}
+	}
+
+	return resourceAppOpticsMetricsBulkRead(d, meta)
+}
+
+// Waits once for all created metrics, rather than once per metric. Each retry
+// only asks for the metrics that didn't exist yet.
+func resourceAppOpticsMetricsBulkWaitForCreation(meta *providerMeta, names []string) error {
+	pending := names
+	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		existing, err := metricsRetrieve(meta, pending)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		missing := make([]string, 0, len(pending))
+		for _, name := range pending {
+			if _, ok := existing[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		pending = missing
+		if len(pending) > 0 {
+			return resource.RetryableError(fmt.Errorf("metric %s doesn't exist yet", pending[0]))
+		}
+		return nil
+	})
+	if retryErr != nil {
+		return fmt.Errorf("Error creating AppOptics metrics: %s", retryErr)
+	}
+	return nil
+}
+
+// Returns the metrics of the given names that exist, by name. They come from
+// the listing of the metric cache when prefetch_metrics is set, otherwise each
+// one is retrieved on its own rather than listing every metric of the
+// account.
+func metricsRetrieve(meta *providerMeta, names []string) (map[string]*appoptics.Metric, error) {
+	metrics := make(map[string]*appoptics.Metric, len(names))
+	for _, name := range names {
+		var metric *appoptics.Metric
+		var err error
+		if meta.metrics != nil {
+			metric, err = meta.metrics.retrieve(meta.client, name)
+		} else {
+			metric, err = meta.client.MetricsService().Retrieve(name)
+		}
+		if err != nil {
+			if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+				continue
+			}
+			return nil, fmt.Errorf("Error reading AppOptics Metric %s: %s", name, err)
+		}
+		metrics[name] = metric
+	}
+	return metrics, nil
+}
+
+// Deletes the metrics with a single request
+func resourceAppOpticsMetricsBulkDeleteNames(meta interface{}, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	client := meta.(*providerMeta).client
+
+	sort.Strings(names)
+	log.Printf("[INFO] Deleting AppOptics metrics %v", names)
+	req, err := client.NewRequest("DELETE", "metrics", &metricsBatchDelete{Names: names})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return fmt.Errorf("Error deleting AppOptics metrics %v: %s", names, err)
+	}
+
+	if cache := meta.(*providerMeta).metrics; cache != nil {
+		for _, name := range names {
+			cache.invalidate(name)
+		}
+	}
+	return nil
+}
+
+func resourceAppOpticsMetricsBulkDelete(d *schema.ResourceData, meta interface{}) error {
+	names := make([]string, 0)
+	for name := range resourceAppOpticsMetricsBulkExpand(nil, d.Get("metric").(*schema.Set)) {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	if err := checkDeletionProtection(d, "metrics", strings.Join(names, ", ")); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := checkMetricDataProtection(meta, name); err != nil {
+			return err
+		}
+	}
+	return resourceAppOpticsMetricsBulkDeleteNames(meta, names)
+}
@@ -0,0 +1,116 @@
+package appoptics
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccAppOpticsMetricsBulkBasic(t *testing.T) {
+	prefix := fmt.Sprintf("tftest-bulk-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsMetricsBulkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsMetricsBulkConfig(prefix, "Milliseconds"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_metrics_bulk.foobar", "metric.#", "3"),
+				),
+			},
+			{
+				PreConfig: sleep(t, 5),
+				Config:    testAccCheckAppOpticsMetricsBulkConfig(prefix, "Seconds"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"appoptics_metrics_bulk.foobar", "metric.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestMetricsBulkGroups(t *testing.T) {
+	ms := appoptics.MetricAttributes{DisplayUnitsShort: "ms"}
+	metrics := map[string]*appoptics.Metric{
+		"api.latency":   {Name: "api.latency", Attributes: ms},
+		"db.latency":    {Name: "db.latency", Attributes: ms},
+		"cache.latency": {Name: "cache.latency", Attributes: ms},
+		"api.requests":  {Name: "api.requests", Period: 60},
+		"api.errors":    {Name: "api.errors", Type: "gauge", Period: 60},
+	}
+
+	groups := metricsBulkGroups(metrics)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+
+	// A new metric isn't updated along with existing ones of the same
+	// definition, its type creates it
+	created := groups[0]
+	groups = groups[1:]
+	if !reflect.DeepEqual(created.Names, []string{"api.errors"}) || created.Type != "gauge" {
+		t.Errorf("unexpected group of the new metric: %#v", created)
+	}
+	if !reflect.DeepEqual(groups[0].Names, []string{"api.latency", "cache.latency", "db.latency"}) {
+		t.Errorf("unexpected names of the first group: %v", groups[0].Names)
+	}
+	if groups[0].Attributes.DisplayUnitsShort != "ms" {
+		t.Errorf("unexpected attributes of the first group: %#v", groups[0].Attributes)
+	}
+	if !reflect.DeepEqual(groups[1].Names, []string{"api.requests"}) || groups[1].Period != 60 {
+		t.Errorf("unexpected second group: %#v", groups[1])
+	}
+}
+
+func testAccCheckAppOpticsMetricsBulkDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "appoptics_metrics_bulk" {
+			continue
+		}
+
+		for k, name := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "metric.") || !strings.HasSuffix(k, ".name") {
+				continue
+			}
+			if _, err := client.MetricsService().Retrieve(name); err == nil {
+				return fmt.Errorf("Metric %s still exists", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAppOpticsMetricsBulkConfig(prefix, units string) string {
+	return fmt.Sprintf(`
+resource "appoptics_metrics_bulk" "foobar" {
+	metric {
+		name = "%[1]s.api.latency"
+		attributes {
+			display_units_long = "%[2]s"
+		}
+	}
+	metric {
+		name = "%[1]s.db.latency"
+		attributes {
+			display_units_long = "%[2]s"
+		}
+	}
+	metric {
+		name        = "%[1]s.api.requests"
+		description = "Requests served by the API"
+	}
+}`, prefix, units)
+}
@@ -20,7 +20,7 @@ func resourceAppOpticsService() *schema.Resource {
 		Update: resourceAppOpticsServiceUpdate,
 		Delete: resourceAppOpticsServiceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAppOpticsServiceImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -38,6 +38,7 @@ func resourceAppOpticsService() *schema.Resource {
 				Required:  true,
 				StateFunc: normalizeJSON,
 			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
@@ -78,14 +79,14 @@ func normalizeJSON(jsonString interface{}) string {
 }
 
 func resourceAppOpticsServiceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	service := new(appoptics.Service)
 	if v, ok := d.GetOk("type"); ok {
 		service.Type = v.(string)
 	}
 	if v, ok := d.GetOk("title"); ok {
-		service.Title = v.(string)
+		service.Title = meta.(*providerMeta).managedTitle(v.(string))
 	}
 	if v, ok := d.GetOk("settings"); ok {
 		res, expandErr := resourceAppOpticsServicesExpandSettings(normalizeJSON(v.(string)))
@@ -117,11 +118,11 @@ func resourceAppOpticsServiceCreate(d *schema.ResourceData, meta interface{}) er
 	}
 
 	d.SetId(strconv.Itoa(serviceResult.ID))
-	return resourceAppOpticsServiceReadResult(d, *serviceResult)
+	return resourceAppOpticsServiceReadResult(d, meta, *serviceResult)
 }
 
 func resourceAppOpticsServiceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
@@ -138,13 +139,13 @@ func resourceAppOpticsServiceRead(d *schema.ResourceData, meta interface{}) erro
 	}
 	log.Printf("[INFO] Received AppOptics Service: %s", service.Title)
 
-	return resourceAppOpticsServiceReadResult(d, *service)
+	return resourceAppOpticsServiceReadResult(d, meta, *service)
 }
 
-func resourceAppOpticsServiceReadResult(d *schema.ResourceData, service appoptics.Service) error {
+func resourceAppOpticsServiceReadResult(d *schema.ResourceData, meta interface{}, service appoptics.Service) error {
 	d.SetId(strconv.FormatUint(uint64(service.ID), 10))
-	d.Set("type", service.Type)   //nolint
-	d.Set("title", service.Title) //nolint
+	d.Set("type", service.Type)                                         //nolint
+	d.Set("title", meta.(*providerMeta).configuredTitle(service.Title)) //nolint
 	settings, _ := resourceAppOpticsServicesFlatten(service.Settings)
 	d.Set("settings", settings) //nolint
 
@@ -152,7 +153,7 @@ func resourceAppOpticsServiceReadResult(d *schema.ResourceData, service appoptic
 }
 
 func resourceAppOpticsServiceUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	serviceID, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
@@ -168,7 +169,7 @@ func resourceAppOpticsServiceUpdate(d *schema.ResourceData, meta interface{}) er
 		service.Type = d.Get("type").(string)
 	}
 	if d.HasChange("title") {
-		service.Title = d.Get("title").(string)
+		service.Title = meta.(*providerMeta).managedTitle(d.Get("title").(string))
 	}
 	if d.HasChange("settings") {
 		res, getErr := resourceAppOpticsServicesExpandSettings(normalizeJSON(d.Get("settings").(string)))
@@ -212,13 +213,57 @@ func resourceAppOpticsServiceUpdate(d *schema.ResourceData, meta interface{}) er
 	return resourceAppOpticsServiceRead(d, meta)
 }
 
+// serviceListResponse is a page of the services list
+type serviceListResponse struct {
+	Query    appoptics.QueryInfo  `json:"query"`
+	Services []*appoptics.Service `json:"services"`
+}
+
+// Returns every service of the account, following the pagination of the API
+func serviceList(client *appoptics.Client) ([]*appoptics.Service, error) {
+	services := make([]*appoptics.Service, 0)
+	err := listPages(client, "services", func() interface{} { return &serviceListResponse{} }, func(page interface{}) (int, int) {
+		p := page.(*serviceListResponse)
+		services = append(services, p.Services...)
+		return len(p.Services), p.Query.Found
+	})
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// Imports a service by its ID, refusing services without the managed marker
+// unless the provider allows them
+func resourceAppOpticsServiceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing AppOptics service ID %s: %s", d.Id(), err)
+	}
+
+	service, err := meta.(*providerMeta).client.ServicesService().Retrieve(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading AppOptics Service %s: %s", d.Id(), err)
+	}
+	if err := meta.(*providerMeta).checkImport("service", service.Title, meta.(*providerMeta).isMarked(service.Title)); err != nil {
+		return nil, err
+	}
+	d.Set("deletion_protection", false) //nolint
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceAppOpticsServiceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
 	}
 
+	if err := checkDeletionProtection(d, "service", d.Get("title").(string)); err != nil {
+		return err
+	}
+
 	log.Printf("[INFO] Deleting Service: %d", id)
 	err = client.ServicesService().Delete(int(id))
 	if err != nil {
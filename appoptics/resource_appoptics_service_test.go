@@ -59,7 +59,7 @@ func TestAccAppOpticsServiceUpdated(t *testing.T) {
 }
 
 func testAccCheckAppOpticsServiceDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*appoptics.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "appoptics_notification_service" {
@@ -93,7 +93,7 @@ func testAccCheckAppOpticsServiceExists(n string, service *appoptics.Service) re
 			return fmt.Errorf("No Service ID is set")
 		}
 
-		client := testAccProvider.Meta().(*appoptics.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		id, err := strconv.ParseUint(rs.Primary.ID, 10, 0)
 		if err != nil {
@@ -3,7 +3,10 @@ package appoptics
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/appoptics/appoptics-api-go"
@@ -17,26 +20,196 @@ func resourceAppOpticsSpace() *schema.Resource {
 		Read:   resourceAppOpticsSpaceRead,
 		Update: resourceAppOpticsSpaceUpdate,
 		Delete: resourceAppOpticsSpaceDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAppOpticsSpaceImport,
+		},
+
+		CustomizeDiff: resourceAppOpticsSpaceCustomizeDiff,
 
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: false,
+		Schema: resourceAppOpticsSpaceSchema(),
+	}
+}
+
+func resourceAppOpticsSpaceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: false,
+		},
+		"deletion_protection": deletionProtectionSchema(),
+		"charts_linking_here": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"space_id": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"chart_id": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"chart_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"tag": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"default_values": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"source_space_id": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"chart"},
+		},
+		"metric_substitution": {
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"from": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+					"to": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+			},
+		},
+		"tag_substitution": {
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+					"from": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+					"to": {
+						Type:     schema.TypeString,
+						Required: true,
+						ForceNew: true,
+					},
+				},
+			},
+		},
+		"chart": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: resourceAppOpticsSpaceChartSchema(resourceAppOpticsSpaceInlineChartSchema()),
 			},
 		},
 	}
 }
 
+// The attributes of an inline chart on top of the shared chart attributes. Its
+// layout is only tracked when configured, otherwise the order of the blocks
+// places it.
+func resourceAppOpticsSpaceInlineChartSchema() map[string]*schema.Schema {
+	s := resourceAppOpticsSpaceChartLayoutSchema()
+	s["id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
+	return s
+}
+
+func resourceAppOpticsSpaceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+
+	declaredTags := make(map[string]bool)
+	for _, tagData := range d.Get("tag").([]interface{}) {
+		if tag, ok := tagData.(map[string]interface{}); ok {
+			declaredTags[tag["name"].(string)] = true
+		}
+	}
+
+	for i, chartData := range d.Get("chart").([]interface{}) {
+		chart, ok := chartData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if d.NewValueKnown(fmt.Sprintf("chart.%d.type", i)) {
+			chartType, _ := chart["type"].(string)
+			bignumber, _ := chart["bignumber"].([]interface{})
+			stacked, _ := chart["stacked"].([]interface{})
+			if err := validateSpaceChartTypeOptions(chartType, bignumber, stacked); err != nil {
+				return fmt.Errorf("chart.%d: %s", i, err)
+			}
+		}
+		if streams, ok := chart["stream"].([]interface{}); ok {
+			if err := validateSpaceChartStreams(streams); err != nil {
+				return fmt.Errorf("chart.%d: %s", i, err)
+			}
+			if err := validateSpaceChartDynamicTags(streams, declaredTags); err != nil {
+				return fmt.Errorf("chart.%d: %s", i, err)
+			}
+		}
+		if d.NewValueKnown(fmt.Sprintf("chart.%d.related_space", i)) {
+			relatedSpaceID, _ := chart["related_space"].(int)
+			if err := validateSpaceChartRelatedSpace(meta.(*providerMeta), relatedSpaceID); err != nil {
+				return fmt.Errorf("chart.%d: %s", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Streams with dynamic tags pick up the tag variables of their dashboard, so
+// those tags have to be among them.
+func validateSpaceChartDynamicTags(streams []interface{}, declaredTags map[string]bool) error {
+	for _, stream := range resourceAppOpticsSpaceChartStreamsExpand(streams, nil) {
+		for _, tag := range stream.Tags {
+			if tag.Dynamic && !declaredTags[tag.Name] {
+				return fmt.Errorf("stream tag %q is dynamic but the dashboard doesn't declare it as a tag variable", tag.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func resourceAppOpticsSpaceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
-	name := d.Get("name").(string)
+	name := meta.(*providerMeta).managedTitle(d.Get("name").(string))
 
 	space, err := client.SpacesService().Create(name)
 	if err != nil {
 		return fmt.Errorf("Error creating AppOptics space %s: %s", name, err)
 	}
+	d.SetId(strconv.Itoa(space.ID))
 
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := client.SpacesService().Retrieve(space.ID)
@@ -53,19 +226,41 @@ func resourceAppOpticsSpaceCreate(d *schema.ResourceData, meta interface{}) erro
 		return retryErr
 	}
 
-	d.SetId(strconv.Itoa(space.ID))
-	return resourceAppOpticsSpaceReadResult(d, space)
+	if v, ok := d.GetOk("source_space_id"); ok {
+		if err := resourceAppOpticsSpaceCopyCharts(d, client, v.(int), space.ID); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("tag", "chart") {
+		update := resourceAppOpticsSpaceUpdateExpand(d, name)
+		if d.HasChange("chart") {
+			chartIDs, err := resourceAppOpticsSpaceReconcileCharts(d, client, space.ID)
+			if err != nil {
+				return err
+			}
+			update.Charts = spaceChartRefs(chartIDs)
+		}
+		if err := spaceUpdate(client, space.ID, update); err != nil {
+			return err
+		}
+	}
+
+	// The new space and its charts have to show up in charts_linking_here
+	meta.(*providerMeta).charts.invalidate(space.ID)
+
+	return resourceAppOpticsSpaceRead(d, meta)
 }
 
 func resourceAppOpticsSpaceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
 	}
 
-	spaceResp, err := client.SpacesService().Retrieve(int(id))
+	spaceResp, err := spaceRetrieve(client, int(id))
 	if err != nil {
 		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
 			d.SetId("")
@@ -74,27 +269,316 @@ func resourceAppOpticsSpaceRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error reading AppOptics Space %s: %s", d.Id(), err)
 	}
 
-	return resourceAppOpticsSpaceReadResult(d, &spaceResp.Space)
+	if err := d.Set("tag", flattenSpaceTags(spaceResp.Tags)); err != nil {
+		return err
+	}
+
+	linkingCharts, err := resourceAppOpticsSpaceLinkingChartsGather(meta.(*providerMeta), int(id))
+	if err != nil {
+		return fmt.Errorf("Error looking up charts linking to AppOptics Space %s: %s", d.Id(), err)
+	}
+	if err := d.Set("charts_linking_here", linkingCharts); err != nil {
+		return err
+	}
+
+	// Charts are only tracked here once they are managed inline, otherwise
+	// they belong to appoptics_dashboard_chart resources. Charts added in the
+	// UI then show up as drift.
+	if _, ok := d.GetOk("chart"); ok {
+		charts, err := resourceAppOpticsSpaceChartsGather(d, meta.(*providerMeta), &spaceResp.RetrieveSpaceResponse)
+		if err != nil {
+			return fmt.Errorf("Error reading charts of AppOptics Space %s: %s", d.Id(), err)
+		}
+		if err := d.Set("chart", charts); err != nil {
+			return err
+		}
+	}
+
+	return resourceAppOpticsSpaceReadResult(d, meta, &spaceResp.Space)
 }
 
-func resourceAppOpticsSpaceReadResult(d *schema.ResourceData, space *appoptics.Space) error {
-	if err := d.Set("name", space.Name); err != nil {
+func resourceAppOpticsSpaceReadResult(d *schema.ResourceData, meta interface{}, space *appoptics.Space) error {
+	if err := d.Set("name", meta.(*providerMeta).configuredTitle(space.Name)); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Returns the charts of a space as inline chart blocks, in display order
+func resourceAppOpticsSpaceChartsGather(d *schema.ResourceData, meta *providerMeta, spaceResp *appoptics.RetrieveSpaceResponse) ([]interface{}, error) {
+	charts, err := meta.charts.list(meta.client, spaceResp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	chartsByID := make(map[int]*spaceChartData, len(charts))
+	for _, chart := range charts {
+		chartsByID[chart.ID] = chart
+	}
+
+	out := make([]interface{}, 0, len(charts))
+	for _, ref := range spaceResp.Charts {
+		if chart, ok := chartsByID[ref["id"]]; ok {
+			out = append(out, resourceAppOpticsSpaceChartFlatten(d, fmt.Sprintf("chart.%d.", len(out)), chart))
+		}
+	}
+
+	return out, nil
+}
+
+// Returns the charts whose related_space is spaceID, by space and chart ID.
+// The chart cache indexes them once per run, however many dashboards are read.
+func resourceAppOpticsSpaceLinkingChartsGather(meta *providerMeta, spaceID int) ([]interface{}, error) {
+	links, err := meta.charts.linking(meta.client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, len(links))
+	for _, link := range links {
+		out = append(out, map[string]interface{}{
+			"space_id":   link.spaceID,
+			"chart_id":   link.chartID,
+			"chart_name": link.chartName,
+		})
+	}
+	return out, nil
+}
+
+// spaceListResponse is a page of the spaces list
+type spaceListResponse struct {
+	Query  appoptics.QueryInfo `json:"query"`
+	Spaces []*appoptics.Space  `json:"spaces"`
+}
+
+// Returns every space of the account, following the pagination of the API
+func spaceList(client *appoptics.Client) ([]*appoptics.Space, error) {
+	spaces := make([]*appoptics.Space, 0)
+	err := listPages(client, "spaces", func() interface{} { return &spaceListResponse{} }, func(page interface{}) (int, int) {
+		p := page.(*spaceListResponse)
+		spaces = append(spaces, p.Spaces...)
+		return len(p.Spaces), p.Query.Found
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+// Creates, updates and deletes the charts of a space so that they match the
+// inline chart blocks. Each block keeps the chart its position had in the
+// state, so renaming a chart updates it in place. Without charts in the state,
+// e.g. after an import, the blocks take over the charts of the space by name.
+// Returns the IDs of the charts in the order of the blocks.
+func resourceAppOpticsSpaceReconcileCharts(d *schema.ResourceData, client *appoptics.Client, spaceID int) ([]int, error) {
+	o, n := d.GetChange("chart")
+
+	existing := make([]*spaceChartData, 0, len(o.([]interface{})))
+	tracked := false
+	for _, chartData := range o.([]interface{}) {
+		chart := resourceAppOpticsSpaceChartExpand(chartData.(map[string]interface{}), nil)
+		existing = append(existing, chart)
+		tracked = tracked || chart.ID != 0
+	}
+
+	var matched, leftovers []*spaceChartData
+	if tracked || d.IsNewResource() {
+		matched, leftovers = matchSpaceChartsByPosition(existing, len(n.([]interface{})))
+	} else {
+		live, err := spaceChartList(client, spaceID)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing charts of AppOptics space %d: %s", spaceID, err)
+		}
+		names := make([]string, 0, len(n.([]interface{})))
+		for _, chartData := range n.([]interface{}) {
+			names = append(names, chartData.(map[string]interface{})["name"].(string))
+		}
+		matched, leftovers = matchSpaceChartsByName(live, names)
+	}
+
+	chartIDs := make([]int, 0, len(n.([]interface{})))
+	for i, chartData := range n.([]interface{}) {
+		chart := resourceAppOpticsSpaceChartExpand(chartData.(map[string]interface{}), resourceDataSetFunc(d, fmt.Sprintf("chart.%d.", i)))
+		chart.ID = 0
+
+		if current := matched[i]; current != nil {
+			if current.Type == chart.Type {
+				chart.ID = current.ID
+				if !reflect.DeepEqual(*current, *chart) {
+					log.Printf("[INFO] Updating chart %d of space %d", chart.ID, spaceID)
+					if err := spaceChartUpdate(client, chart, spaceID, spaceChartClearedBounds(current, chart)...); err != nil {
+						return nil, fmt.Errorf("Error updating AppOptics chart %s: %s", chart.Name, err)
+					}
+				}
+			} else {
+				// The chart type can't be changed in place
+				if err := resourceAppOpticsSpaceDeleteChart(client, spaceID, current.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if chart.ID == 0 {
+			log.Printf("[INFO] Creating chart %s in space %d", chart.Name, spaceID)
+			created, err := spaceChartCreate(client, chart, spaceID)
+			if err != nil {
+				return nil, fmt.Errorf("Error creating AppOptics chart %s: %s", chart.Name, err)
+			}
+			chart.ID = created.ID
+		}
+
+		chartIDs = append(chartIDs, chart.ID)
+	}
+
+	for _, chart := range leftovers {
+		if err := resourceAppOpticsSpaceDeleteChart(client, spaceID, chart.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return chartIDs, nil
+}
+
+// Matches count blocks to the charts of the state by position. Returns the
+// chart of each block, nil for new blocks, and the charts no block kept.
+// Charts the state has without an ID are left out.
+func matchSpaceChartsByPosition(existing []*spaceChartData, count int) ([]*spaceChartData, []*spaceChartData) {
+	matched := make([]*spaceChartData, count)
+	leftovers := make([]*spaceChartData, 0)
+	for i, chart := range existing {
+		if chart.ID == 0 {
+			continue
+		}
+		if i < count {
+			matched[i] = chart
+		} else {
+			leftovers = append(leftovers, chart)
+		}
+	}
+	return matched, leftovers
+}
+
+// Matches blocks to the live charts of a space by name, using each chart at
+// most once. Returns the chart of each block, nil for new blocks, and the
+// charts no block took.
+func matchSpaceChartsByName(live []*spaceChartData, names []string) ([]*spaceChartData, []*spaceChartData) {
+	sort.Slice(live, func(i, j int) bool { return live[i].ID < live[j].ID })
+
+	used := make(map[int]bool)
+	matched := make([]*spaceChartData, len(names))
+	for i, name := range names {
+		for _, chart := range live {
+			if chart.Name == name && !used[chart.ID] {
+				matched[i] = chart
+				used[chart.ID] = true
+				break
+			}
+		}
+	}
+
+	leftovers := make([]*spaceChartData, 0)
+	for _, chart := range live {
+		if !used[chart.ID] {
+			leftovers = append(leftovers, chart)
+		}
+	}
+	return matched, leftovers
+}
+
+// Copies the charts of another space, in display order, applying the metric
+// and tag substitutions to their streams
+func resourceAppOpticsSpaceCopyCharts(d *schema.ResourceData, client *appoptics.Client, sourceID, spaceID int) error {
+	sourceResp, err := client.SpacesService().Retrieve(sourceID)
+	if err != nil {
+		return fmt.Errorf("Error reading source AppOptics space %d: %s", sourceID, err)
+	}
+
+	metricSubstitutions := d.Get("metric_substitution").([]interface{})
+	tagSubstitutions := d.Get("tag_substitution").([]interface{})
+
+	for _, ref := range sourceResp.Charts {
+		chart, err := spaceChartRetrieve(client, ref["id"], sourceID)
+		if err != nil {
+			return fmt.Errorf("Error reading chart %d of source AppOptics space %d: %s", ref["id"], sourceID, err)
+		}
+
+		chart.ID = 0
+		for i := range chart.Streams {
+			chart.Streams[i].ID = 0
+		}
+		applySpaceChartSubstitutions(chart, metricSubstitutions, tagSubstitutions)
+
+		log.Printf("[INFO] Copying chart %d of space %d to space %d", ref["id"], sourceID, spaceID)
+		if _, err := spaceChartCreate(client, chart, spaceID); err != nil {
+			return fmt.Errorf("Error copying chart %s to AppOptics space %d: %s", chart.Name, spaceID, err)
+		}
+	}
+
+	return nil
+}
+
+// Rewrites the metrics and tag values used by the streams of a chart. Metric
+// substitutions replace every occurrence of from in metric names and composite
+// queries, tag substitutions replace tag values equal to from for the tag name.
+func applySpaceChartSubstitutions(chart *spaceChartData, metricSubstitutions, tagSubstitutions []interface{}) {
+	for i := range chart.Streams {
+		stream := &chart.Streams[i]
+
+		for _, substitutionData := range metricSubstitutions {
+			substitution := substitutionData.(map[string]interface{})
+			from, to := substitution["from"].(string), substitution["to"].(string)
+			stream.Metric = strings.ReplaceAll(stream.Metric, from, to)
+			stream.Composite = strings.ReplaceAll(stream.Composite, from, to)
+		}
+
+		for _, tag := range stream.Tags {
+			for _, substitutionData := range tagSubstitutions {
+				substitution := substitutionData.(map[string]interface{})
+				if tag.Name != substitution["name"].(string) {
+					continue
+				}
+				for j, value := range tag.Values {
+					if value == substitution["from"].(string) {
+						tag.Values[j] = substitution["to"].(string)
+					}
+				}
+			}
+		}
+	}
+}
+
+func resourceAppOpticsSpaceDeleteChart(client *appoptics.Client, spaceID, chartID int) error {
+	log.Printf("[INFO] Deleting chart %d of space %d", chartID, spaceID)
+	if err := client.ChartsService().Delete(chartID, spaceID); err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			return nil
+		}
+		return fmt.Errorf("Error deleting chart %d: %s", chartID, err)
+	}
+	return nil
+}
+
 func resourceAppOpticsSpaceUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
 	}
 
-	if d.HasChange("name") {
-		newName := d.Get("name").(string)
-		log.Printf("[INFO] Modifying name space attribute for %d: %#v", id, newName)
-		if err = client.SpacesService().Update(int(id), newName); err != nil {
+	name := meta.(*providerMeta).managedTitle(d.Get("name").(string))
+	update := resourceAppOpticsSpaceUpdateExpand(d, name)
+	if d.HasChange("chart") {
+		chartIDs, err := resourceAppOpticsSpaceReconcileCharts(d, client, int(id))
+		meta.(*providerMeta).charts.invalidate(int(id))
+		if err != nil {
+			return err
+		}
+		update.Charts = spaceChartRefs(chartIDs)
+	}
+
+	if d.HasChanges("name", "tag", "chart") {
+		log.Printf("[INFO] Modifying space %d: %#v", id, update)
+		if err := spaceUpdate(client, int(id), update); err != nil {
 			return err
 		}
 	}
@@ -102,13 +586,164 @@ func resourceAppOpticsSpaceUpdate(d *schema.ResourceData, meta interface{}) erro
 	return resourceAppOpticsSpaceRead(d, meta)
 }
 
+// spaceData is the space document of the spaces API. It extends
+// appoptics.RetrieveSpaceResponse with the dashboard tag variables, which the
+// client library doesn't model yet.
+type spaceData struct {
+	appoptics.RetrieveSpaceResponse
+	Tags []spaceTag `json:"tags,omitempty"`
+}
+
+// spaceTag is a tag variable dashboards can be filtered by
+type spaceTag struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values,omitempty"`
+}
+
+// spaceUpdateRequest is the payload used to update a space, which the
+// SpacesService client can only rename. The tag variables and the chart order
+// are kept as they are when left out.
+type spaceUpdateRequest struct {
+	Name   string            `json:"name"`
+	Tags   *[]spaceTag       `json:"tags,omitempty"`
+	Charts *[]map[string]int `json:"charts,omitempty"`
+}
+
+func spaceRetrieve(client *appoptics.Client, spaceID int) (*spaceData, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("spaces/%d", spaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	space := &spaceData{}
+	if _, err := client.Do(req, space); err != nil {
+		return nil, err
+	}
+	return space, nil
+}
+
+// Returns the update of a space to name, with its tag variables when they
+// changed
+func resourceAppOpticsSpaceUpdateExpand(d *schema.ResourceData, name string) *spaceUpdateRequest {
+	update := &spaceUpdateRequest{Name: name}
+	if !d.HasChange("tag") {
+		return update
+	}
+
+	tags := make([]spaceTag, 0)
+	for _, tagData := range d.Get("tag").([]interface{}) {
+		tagMap := tagData.(map[string]interface{})
+		tag := spaceTag{Name: tagMap["name"].(string)}
+		for _, v := range tagMap["default_values"].([]interface{}) {
+			tag.Values = append(tag.Values, v.(string))
+		}
+		tags = append(tags, tag)
+	}
+	update.Tags = &tags
+	return update
+}
+
+// Returns the chart order of a space update
+func spaceChartRefs(chartIDs []int) *[]map[string]int {
+	refs := make([]map[string]int, len(chartIDs))
+	for i, chartID := range chartIDs {
+		refs[i] = map[string]int{"id": chartID}
+	}
+	return &refs
+}
+
+// Updates a space in a single request. When the update orders the charts, it
+// waits until the API reports the order back.
+func spaceUpdate(client *appoptics.Client, spaceID int, update *spaceUpdateRequest) error {
+	req, err := client.NewRequest("PUT", fmt.Sprintf("spaces/%d", spaceID), update)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		return fmt.Errorf("Error updating AppOptics space %d: %s", spaceID, err)
+	}
+
+	if update.Charts == nil {
+		return nil
+	}
+	chartIDs := make([]int, 0, len(*update.Charts))
+	for _, ref := range *update.Charts {
+		chartIDs = append(chartIDs, ref["id"])
+	}
+
+	return resource.Retry(1*time.Minute, func() *resource.RetryError {
+		spaceResp, err := client.SpacesService().Retrieve(spaceID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		current := make([]int, 0, len(spaceResp.Charts))
+		for _, ref := range spaceResp.Charts {
+			current = append(current, ref["id"])
+		}
+		if !reflect.DeepEqual(current, chartIDs) {
+			return resource.RetryableError(fmt.Errorf("charts of space %d not in order yet", spaceID))
+		}
+		return nil
+	})
+}
+
+func flattenSpaceTags(in []spaceTag) []interface{} {
+	out := make([]interface{}, 0, len(in))
+	for _, v := range in {
+		values := make([]interface{}, 0, len(v.Values))
+		for _, value := range v.Values {
+			values = append(values, value)
+		}
+		out = append(out, map[string]interface{}{
+			"name":           v.Name,
+			"default_values": values,
+		})
+	}
+	return out
+}
+
+// Imports a space by its ID, refusing spaces without the managed marker unless
+// the provider allows them
+func resourceAppOpticsSpaceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing AppOptics space ID %s: %s", d.Id(), err)
+	}
+
+	space, err := meta.(*providerMeta).client.SpacesService().Retrieve(id)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading AppOptics Space %s: %s", d.Id(), err)
+	}
+	if err := meta.(*providerMeta).checkImport("dashboard", space.Name, meta.(*providerMeta).isMarked(space.Name)); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Returns the configured name of an appoptics_dashboard or the name in the
+// document of an appoptics_dashboard_json, which share their delete
+func resourceAppOpticsSpaceConfiguredName(d *schema.ResourceData) string {
+	if rawDocument, ok := d.Get("json").(string); ok {
+		if document, err := resourceAppOpticsSpaceJSONExpand(rawDocument); err == nil {
+			return document.Name
+		}
+		return ""
+	}
+	return d.Get("name").(string)
+}
+
 func resourceAppOpticsSpaceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 	id, err := strconv.ParseUint(d.Id(), 10, 0)
 	if err != nil {
 		return err
 	}
 
+	if err := checkDeletionProtection(d, "dashboard", resourceAppOpticsSpaceConfiguredName(d)); err != nil {
+		return err
+	}
+
 	log.Printf("[INFO] Deleting Space: %d", id)
 	err = client.SpacesService().Delete(int(id))
 	if err != nil {
@@ -119,6 +754,8 @@ func resourceAppOpticsSpaceDelete(d *schema.ResourceData, meta interface{}) erro
 		}
 		return fmt.Errorf("Error deleting space: %s", err)
 	}
+	meta.(*providerMeta).charts.invalidate(int(id))
+	meta.(*providerMeta).spaces.invalidate(int(id))
 
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := client.SpacesService().Retrieve(int(id))
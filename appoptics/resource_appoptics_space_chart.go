@@ -1,7 +1,7 @@
 package appoptics
 
 import (
-	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/appoptics/appoptics-api-go"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func resourceAppOpticsSpaceChart() *schema.Resource {
@@ -22,170 +22,356 @@ func resourceAppOpticsSpaceChart() *schema.Resource {
 		Update: resourceAppOpticsSpaceChartUpdate,
 		Delete: resourceAppOpticsSpaceChartDelete,
 
-		Schema: map[string]*schema.Schema{
-			"space_id": {
-				Type:     schema.TypeInt,
-				Required: true,
-				ForceNew: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Optional: true,
-			},
-			"min": {
-				Type:     schema.TypeFloat,
-				Optional: true,
-			},
-			"max": {
-				Type:     schema.TypeFloat,
-				Optional: true,
+		CustomizeDiff: resourceAppOpticsSpaceChartCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceAppOpticsSpaceChartResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAppOpticsSpaceChartStateUpgradeV0,
+				Version: 0,
 			},
-			"label": {
-				Type:     schema.TypeString,
-				Optional: true,
+		},
+
+		Schema: resourceAppOpticsSpaceChartStandaloneSchema(),
+	}
+}
+
+// A standalone chart is replaced when its type changes. Inline charts aren't,
+// the reconcile of their dashboard recreates just that chart instead.
+func resourceAppOpticsSpaceChartStandaloneSchema() map[string]*schema.Schema {
+	s := resourceAppOpticsSpaceChartSchema(resourceAppOpticsSpaceChartPlacementSchema())
+	s["type"].ForceNew = true
+	s["deletion_protection"] = deletionProtectionSchema()
+
+	return s
+}
+
+// The attributes placing a standalone chart on its dashboard. Its position is
+// read back even when it isn't configured.
+func resourceAppOpticsSpaceChartPlacementSchema() map[string]*schema.Schema {
+	s := resourceAppOpticsSpaceChartLayoutSchema()
+	for _, v := range s {
+		v.Computed = true
+	}
+	s["space_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+		ForceNew: true,
+	}
+
+	return s
+}
+
+// The position and size of a chart on the dashboard grid
+func resourceAppOpticsSpaceChartLayoutSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"row": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"column": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"width": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"height": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+	}
+}
+
+// resourceAppOpticsSpaceChartSchema returns the chart attributes shared by
+// appoptics_dashboard_chart and the inline chart blocks of appoptics_dashboard.
+// extra is merged into the result.
+func resourceAppOpticsSpaceChartSchema(extra map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"min": {
+			Type:     schema.TypeFloat,
+			Optional: true,
+		},
+		"max": {
+			Type:     schema.TypeFloat,
+			Optional: true,
+		},
+		"label": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"related_space": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"bignumber": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"use_last_value": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"threshold": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"operator": {
+									Type:         schema.TypeString,
+									Required:     true,
+									ValidateFunc: validation.StringInSlice([]string{">", "<", "="}, false),
+								},
+								"value": {
+									Type:     schema.TypeFloat,
+									Required: true,
+								},
+								"color": {
+									Type:         schema.TypeString,
+									Required:     true,
+									ValidateFunc: validation.StringInSlice([]string{"red", "yellow", "green"}, false),
+								},
+							},
+						},
+					},
+				},
 			},
-			"related_space": {
-				Type:     schema.TypeInt,
-				Optional: true,
+		},
+		"stacked": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"mode": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "absolute",
+						ValidateFunc: validation.StringInSlice([]string{"absolute", "percent"}, false),
+					},
+				},
 			},
-			"stream": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"metric": {
-							Type:          schema.TypeString,
-							Optional:      true,
-							ConflictsWith: []string{"stream.composite"},
-						},
-						"tags": {
-							Type:          schema.TypeList,
-							Optional:      true,
-							ConflictsWith: []string{"stream.composite"},
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Type:     schema.TypeString,
-										Optional: true,
-									},
-									"grouped": {
-										Type:     schema.TypeBool,
-										Optional: true,
-									},
-									"dynamic": {
-										Type:     schema.TypeBool,
-										Optional: true,
-									},
-									"values": {
-										Type:     schema.TypeList,
-										Optional: true,
-										Elem:     &schema.Schema{Type: schema.TypeString},
-									},
+		},
+		"stream": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					// metric, tags and group_function conflict with composite,
+					// see validateSpaceChartStreams
+					"metric": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"tags": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+								"grouped": {
+									Type:     schema.TypeBool,
+									Optional: true,
+								},
+								"dynamic": {
+									Type:     schema.TypeBool,
+									Optional: true,
+								},
+								"values": {
+									Type:     schema.TypeList,
+									Optional: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
 								},
 							},
 						},
-						"group_function": {
-							Type:          schema.TypeString,
-							Optional:      true,
-							ConflictsWith: []string{"stream.composite"},
-						},
-						"composite": {
-							Type:          schema.TypeString,
-							Optional:      true,
-							ConflictsWith: []string{"stream.metric", "stream.group_function"},
-						},
-						"summary_function": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"color": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"units_short": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"units_long": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"min": {
-							Type:     schema.TypeInt,
-							Optional: true,
-						},
-						"max": {
-							Type:     schema.TypeInt,
-							Optional: true,
-						},
-						"transform_function": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"period": {
-							Type:     schema.TypeInt,
-							Optional: true,
-						},
+					},
+					"group_function": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"composite": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"summary_function": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"color": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"units_short": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"units_long": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"min": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"max": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"transform_function": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"period": {
+						Type:     schema.TypeInt,
+						Optional: true,
 					},
 				},
-				Set: resourceAppOpticsSpaceChartHash,
 			},
 		},
 	}
+
+	for k, v := range extra {
+		s[k] = v
+	}
+
+	return s
 }
 
-func resourceAppOpticsSpaceChartHash(v interface{}) int {
-	var buf bytes.Buffer
-	m := v.(map[string]interface{})
-	buf.WriteString(fmt.Sprintf("%s-", m["metric"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["composite"].(string)))
-	// TOOD: is this under "stream"?
-	tags, present := m["tag"].([]interface{})
-	if present && len(tags) > 0 {
-		buf.WriteString(fmt.Sprintf("%d-", chartStreamTagsHash(tags)))
+func resourceAppOpticsSpaceChartCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateSpaceChartStreams(d.Get("stream").([]interface{})); err != nil {
+		return err
 	}
 
-	return hashcode.String(buf.String())
+	if d.NewValueKnown("type") {
+		if err := validateSpaceChartTypeOptions(d.Get("type").(string), d.Get("bignumber").([]interface{}), d.Get("stacked").([]interface{})); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("related_space") {
+		return validateSpaceChartRelatedSpace(meta.(*providerMeta), d.Get("related_space").(int))
+	}
+	return nil
 }
 
-func chartStreamTagsHash(tags []interface{}) int {
-	var buf bytes.Buffer
-	for _, v := range tags {
-		m := v.(map[string]interface{})
-		buf.WriteString(fmt.Sprintf("%s-", m["name"]))
-		buf.WriteString(fmt.Sprintf("%s-", m["grouped"]))
-		buf.WriteString(fmt.Sprintf("%s-", m["dynamic"]))
-		buf.WriteString(fmt.Sprintf("%d-", chartStreamTagsValuesHash(m["values"].([]interface{}))))
+// Drill-down links to a deleted space are silently broken in the UI, so make
+// sure the related space exists
+func validateSpaceChartRelatedSpace(meta *providerMeta, relatedSpaceID int) error {
+	if relatedSpaceID == 0 {
+		return nil
 	}
 
-	return hashcode.String(buf.String())
+	exists, err := meta.spaces.check(meta.client, relatedSpaceID)
+	if err != nil {
+		return fmt.Errorf("Error reading related AppOptics space %d: %s", relatedSpaceID, err)
+	}
+	if !exists {
+		return fmt.Errorf("related_space %d doesn't exist", relatedSpaceID)
+	}
+	return nil
 }
 
-func chartStreamTagsValuesHash(s []interface{}) int {
-	var buf bytes.Buffer
-	for _, v := range s {
-		buf.WriteString(fmt.Sprintf("%s-", v))
+// Checks the dynamic stream tags against the tag variables the dashboard has
+// in AppOptics. It runs on apply rather than on plan, once a dashboard the
+// chart depends on has its new tag variables. The dashboard is only read when
+// a stream has dynamic tags.
+func resourceAppOpticsSpaceChartValidateDynamicTags(client *appoptics.Client, spaceID int, streams []interface{}) error {
+	hasDynamicTags := false
+	for _, stream := range resourceAppOpticsSpaceChartStreamsExpand(streams, nil) {
+		for _, tag := range stream.Tags {
+			hasDynamicTags = hasDynamicTags || tag.Dynamic
+		}
 	}
+	if !hasDynamicTags {
+		return nil
+	}
+
+	space, err := spaceRetrieve(client, spaceID)
+	if err != nil {
+		return fmt.Errorf("Error reading AppOptics space %d: %s", spaceID, err)
+	}
+
+	declaredTags := make(map[string]bool, len(space.Tags))
+	for _, tag := range space.Tags {
+		declaredTags[tag.Name] = true
+	}
+
+	return validateSpaceChartDynamicTags(streams, declaredTags)
+}
+
+// A composite stream is a query of its own, so it can't also name a metric.
+// ConflictsWith can't express this for the elements of a list.
+func validateSpaceChartStreams(streams []interface{}) error {
+	for i, streamData := range streams {
+		stream, ok := streamData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if composite, _ := stream["composite"].(string); composite == "" {
+			continue
+		}
+		for _, k := range []string{"metric", "group_function"} {
+			if v, _ := stream[k].(string); v != "" {
+				return fmt.Errorf("stream.%d: %s conflicts with composite", i, k)
+			}
+		}
+		if tags, _ := stream["tags"].([]interface{}); len(tags) > 0 {
+			return fmt.Errorf("stream.%d: tags conflicts with composite", i)
+		}
+	}
+	return nil
+}
 
-	return hashcode.String(buf.String())
+// Type specific option blocks only make sense for the matching chart type
+func validateSpaceChartTypeOptions(chartType string, bignumber, stacked []interface{}) error {
+	if chartType == "" {
+		chartType = "line"
+	}
+	if len(bignumber) > 0 && chartType != "bignumber" {
+		return fmt.Errorf("bignumber options can only be set on bignumber charts, not on %s charts", chartType)
+	}
+	if len(stacked) > 0 && chartType != "stacked" {
+		return fmt.Errorf("stacked options can only be set on stacked charts, not on %s charts", chartType)
+	}
+	return nil
 }
 
 func resourceAppOpticsSpaceChartCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	spaceID := d.Get("space_id").(int)
 
-	spaceChart := &appoptics.Chart{
-		Type: "line", // default per docs
+	if err := resourceAppOpticsSpaceChartValidateDynamicTags(client, spaceID, d.Get("stream").([]interface{})); err != nil {
+		return err
+	}
+
+	spaceChart := &spaceChartData{
+		Chart: appoptics.Chart{
+			Type: "line", // default per docs
+		},
 	}
 
 	if v, ok := d.GetOk("name"); ok {
@@ -194,17 +380,19 @@ func resourceAppOpticsSpaceChartCreate(d *schema.ResourceData, meta interface{})
 	if v, ok := d.GetOk("type"); ok {
 		spaceChart.Type = v.(string)
 	}
-	if v, ok := d.GetOk("min"); ok {
+	if v, ok := d.GetOkExists("min"); ok {
 		if math.IsNaN(v.(float64)) {
 			return fmt.Errorf("Error creating AppOptics space chart. 'min' cannot be converted to a float64. %s", d.Get("min"))
 		}
-		spaceChart.Min = v.(float64)
+		min := v.(float64)
+		spaceChart.Min = &min
 	}
-	if v, ok := d.GetOk("max"); ok {
+	if v, ok := d.GetOkExists("max"); ok {
 		if math.IsNaN(v.(float64)) {
 			return fmt.Errorf("Error creating AppOptics space chart. 'max' cannot be converted to a float64. %s", d.Get("max"))
 		}
-		spaceChart.Max = v.(float64)
+		max := v.(float64)
+		spaceChart.Max = &max
 	}
 	if v, ok := d.GetOk("label"); ok {
 		spaceChart.Label = v.(string)
@@ -213,53 +401,16 @@ func resourceAppOpticsSpaceChartCreate(d *schema.ResourceData, meta interface{})
 		spaceChart.RelatedSpace = v.(int)
 	}
 	if v, ok := d.GetOk("stream"); ok {
-		vs := v.(*schema.Set)
-		streams := make([]appoptics.Stream, vs.Len())
-		for i, streamDataM := range vs.List() {
-			streamData := streamDataM.(map[string]interface{})
-			var stream appoptics.Stream
-			if v, ok := streamData["metric"].(string); ok && v != "" {
-				stream.Metric = v
-			}
-			if v, ok := streamData["tags"].([]appoptics.Tag); ok {
-				stream.Tags = v
-			}
-			if v, ok := streamData["composite"].(string); ok && v != "" {
-				stream.Composite = v
-			}
-			if v, ok := streamData["group_function"].(string); ok && v != "" {
-				stream.GroupFunction = v
-			}
-			if v, ok := streamData["summary_function"].(string); ok && v != "" {
-				stream.SummaryFunction = v
-			}
-			if v, ok := streamData["transform_function"].(string); ok && v != "" {
-				stream.TransformFunction = v
-			}
-			if v, ok := streamData["color"].(string); ok && v != "" {
-				stream.Color = v
-			}
-			if v, ok := streamData["units_short"].(string); ok && v != "" {
-				stream.UnitsShort = v
-			}
-			if v, ok := streamData["units_longs"].(string); ok && v != "" {
-				stream.UnitsLong = v
-			}
-			if v, ok := streamData["min"].(float64); ok && !math.IsNaN(v) {
-				stream.Min = int(v)
-			}
-			if v, ok := streamData["max"].(float64); ok && !math.IsNaN(v) {
-				stream.Max = int(v)
-			}
-			streams[i] = stream
-		}
-		spaceChart.Streams = streams
+		spaceChart.Streams = resourceAppOpticsSpaceChartStreamsExpand(v.([]interface{}), resourceDataSetFunc(d, ""))
 	}
+	spaceChart.Layout = resourceAppOpticsSpaceChartLayoutExpand(d)
+	resourceAppOpticsSpaceChartTypeOptionsExpand(spaceChart, d.Get("bignumber").([]interface{}), d.Get("stacked").([]interface{}))
 
-	spaceChartResult, err := client.ChartsService().Create(spaceChart, spaceID)
+	spaceChartResult, err := spaceChartCreate(client, spaceChart, spaceID)
 	if err != nil {
 		return fmt.Errorf("Error creating AppOptics chart %s: %s", spaceChart.Name, err)
 	}
+	meta.(*providerMeta).charts.invalidate(spaceID)
 
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := client.ChartsService().Retrieve(spaceChartResult.ID, spaceID)
@@ -280,7 +431,8 @@ func resourceAppOpticsSpaceChartCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceAppOpticsSpaceChartRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
+	cache := meta.(*providerMeta).charts
 
 	spaceID := d.Get("space_id").(int)
 
@@ -289,7 +441,7 @@ func resourceAppOpticsSpaceChartRead(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
-	chart, err := client.ChartsService().Retrieve(id, spaceID)
+	chart, err := cache.retrieve(client, id, spaceID)
 	if err != nil {
 		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
 			d.SetId("")
@@ -301,7 +453,7 @@ func resourceAppOpticsSpaceChartRead(d *schema.ResourceData, meta interface{}) e
 	return resourceAppOpticsSpaceChartReadResult(d, chart)
 }
 
-func resourceAppOpticsSpaceChartReadResult(d *schema.ResourceData, chart *appoptics.Chart) error {
+func resourceAppOpticsSpaceChartReadResult(d *schema.ResourceData, chart *spaceChartData) error {
 	d.SetId(strconv.FormatUint(uint64(chart.ID), 10))
 	if err := d.Set("name", chart.Name); err != nil {
 		return err
@@ -309,10 +461,10 @@ func resourceAppOpticsSpaceChartReadResult(d *schema.ResourceData, chart *appopt
 	if err := d.Set("type", chart.Type); err != nil {
 		return err
 	}
-	if err := d.Set("min", chart.Min); err != nil {
+	if err := d.Set("min", flattenOptionalFloat(chart.Min)); err != nil {
 		return err
 	}
-	if err := d.Set("max", chart.Max); err != nil {
+	if err := d.Set("max", flattenOptionalFloat(chart.Max)); err != nil {
 		return err
 	}
 	if err := d.Set("label", chart.Label); err != nil {
@@ -327,10 +479,290 @@ func resourceAppOpticsSpaceChartReadResult(d *schema.ResourceData, chart *appopt
 		return err
 	}
 
+	_, hasBignumber := d.GetOk("bignumber")
+	_, hasStacked := d.GetOk("stacked")
+	bignumber, stacked := resourceAppOpticsSpaceChartTypeOptionsGather(chart, hasBignumber, hasStacked)
+	if err := d.Set("bignumber", bignumber); err != nil {
+		return err
+	}
+	if err := d.Set("stacked", stacked); err != nil {
+		return err
+	}
+
+	return resourceAppOpticsSpaceChartLayoutGather(d, chart.Layout)
+}
+
+// Sets the bignumber and stacked options of a chart from their blocks. The
+// options are always sent so that removing a block clears them.
+func resourceAppOpticsSpaceChartTypeOptionsExpand(chart *spaceChartData, bignumber, stacked []interface{}) {
+	switch chart.Type {
+	case "bignumber":
+		useLastValue := false
+		thresholds := make([]spaceChartThreshold, 0)
+		if len(bignumber) > 0 && bignumber[0] != nil {
+			options := bignumber[0].(map[string]interface{})
+			useLastValue = options["use_last_value"].(bool)
+			for _, thresholdData := range options["threshold"].([]interface{}) {
+				threshold := thresholdData.(map[string]interface{})
+				thresholds = append(thresholds, spaceChartThreshold{
+					Operator: threshold["operator"].(string),
+					Value:    threshold["value"].(float64),
+					Type:     threshold["color"].(string),
+				})
+			}
+		}
+		chart.UseLastValue = &useLastValue
+		chart.Thresholds = &thresholds
+	case "stacked":
+		stacking := "absolute"
+		if len(stacked) > 0 && stacked[0] != nil {
+			stacking = stacked[0].(map[string]interface{})["mode"].(string)
+		}
+		chart.Stacking = &stacking
+	}
+}
+
+// Returns the bignumber and stacked blocks of a chart. Unless the blocks are
+// known to be configured, options left at their defaults aren't returned so
+// that charts without the blocks don't show a diff.
+func resourceAppOpticsSpaceChartTypeOptionsGather(chart *spaceChartData, keepBignumber, keepStacked bool) ([]interface{}, []interface{}) {
+	bignumber := make([]interface{}, 0, 1)
+	stacked := make([]interface{}, 0, 1)
+
+	useLastValue := chart.UseLastValue != nil && *chart.UseLastValue
+	var thresholds []spaceChartThreshold
+	if chart.Thresholds != nil {
+		thresholds = *chart.Thresholds
+	}
+	if chart.Type == "bignumber" && (keepBignumber || useLastValue || len(thresholds) > 0) {
+		retThresholds := make([]interface{}, 0, len(thresholds))
+		for _, t := range thresholds {
+			retThresholds = append(retThresholds, map[string]interface{}{
+				"operator": t.Operator,
+				"value":    t.Value,
+				"color":    t.Type,
+			})
+		}
+		bignumber = append(bignumber, map[string]interface{}{
+			"use_last_value": useLastValue,
+			"threshold":      retThresholds,
+		})
+	}
+
+	stacking := "absolute"
+	if chart.Stacking != nil && *chart.Stacking != "" {
+		stacking = *chart.Stacking
+	}
+	if chart.Type == "stacked" && (keepStacked || stacking != "absolute") {
+		stacked = append(stacked, map[string]interface{}{
+			"mode": stacking,
+		})
+	}
+
+	return bignumber, stacked
+}
+
+// Builds the layout of a chart from its position and size attributes. Only
+// the attributes set in the config are sent, the API keeps the others.
+func resourceAppOpticsSpaceChartLayoutExpand(d *schema.ResourceData) *spaceChartLayout {
+	layout := &spaceChartLayout{}
+	if v, ok := d.GetOk("row"); ok {
+		layout.Row = v.(int)
+	}
+	if v, ok := d.GetOk("column"); ok {
+		layout.Col = v.(int)
+	}
+	if v, ok := d.GetOk("width"); ok {
+		layout.SizeX = v.(int)
+	}
+	if v, ok := d.GetOk("height"); ok {
+		layout.SizeY = v.(int)
+	}
+
+	if *layout == (spaceChartLayout{}) {
+		return nil
+	}
+	return layout
+}
+
+func resourceAppOpticsSpaceChartLayoutGather(d *schema.ResourceData, layout *spaceChartLayout) error {
+	if layout == nil {
+		return nil
+	}
+
+	current := map[string]int{
+		"row":    layout.Row,
+		"column": layout.Col,
+		"width":  layout.SizeX,
+		"height": layout.SizeY,
+	}
+	for k, v := range current {
+		// Charts are easily dragged around in the UI, so call out where the
+		// drift comes from when a known position changes under us.
+		if old, ok := d.GetOk(k); ok && old.(int) != v {
+			log.Printf("[WARN] AppOptics chart %s was moved outside of Terraform: %s changed from %d to %d", d.Id(), k, old.(int), v)
+		}
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func resourceAppOpticsSpaceChartStreamsGather(d *schema.ResourceData, streams []appoptics.Stream) []map[string]interface{} {
+// Expands an inline chart block of appoptics_dashboard into its API
+// representation. isSet tells explicit zeros apart, paths are relative to the
+// block.
+func resourceAppOpticsSpaceChartExpand(chartData map[string]interface{}, isSet attributeSetFunc) *spaceChartData {
+	chart := &spaceChartData{
+		Chart: appoptics.Chart{
+			Type: "line", // default per docs
+		},
+	}
+
+	if v, ok := chartData["id"].(int); ok {
+		chart.ID = v
+	}
+	if v, ok := chartData["name"].(string); ok {
+		chart.Name = v
+	}
+	if v, ok := chartData["type"].(string); ok && v != "" {
+		chart.Type = v
+	}
+	chart.Min = expandOptionalFloat(chartData["min"], "min", isSet)
+	chart.Max = expandOptionalFloat(chartData["max"], "max", isSet)
+	if v, ok := chartData["label"].(string); ok {
+		chart.Label = v
+	}
+	if v, ok := chartData["related_space"].(int); ok {
+		chart.RelatedSpace = v
+	}
+	if v, ok := chartData["stream"].([]interface{}); ok {
+		chart.Streams = resourceAppOpticsSpaceChartStreamsExpand(v, isSet)
+	}
+	bignumber, _ := chartData["bignumber"].([]interface{})
+	stacked, _ := chartData["stacked"].([]interface{})
+	resourceAppOpticsSpaceChartTypeOptionsExpand(chart, bignumber, stacked)
+
+	layout := &spaceChartLayout{}
+	layout.Row, _ = chartData["row"].(int)
+	layout.Col, _ = chartData["column"].(int)
+	layout.SizeX, _ = chartData["width"].(int)
+	layout.SizeY, _ = chartData["height"].(int)
+	if *layout != (spaceChartLayout{}) {
+		chart.Layout = layout
+	}
+
+	return chart
+}
+
+// Flattens a chart into an inline chart block of appoptics_dashboard. Only the
+// layout attributes configured on the block at prefix are returned, so that
+// charts placed by their order don't show a diff.
+func resourceAppOpticsSpaceChartFlatten(d *schema.ResourceData, prefix string, chart *spaceChartData) map[string]interface{} {
+	bignumber, stacked := resourceAppOpticsSpaceChartTypeOptionsGather(chart, false, false)
+	out := map[string]interface{}{
+		"bignumber":     bignumber,
+		"stacked":       stacked,
+		"id":            chart.ID,
+		"name":          chart.Name,
+		"type":          chart.Type,
+		"min":           flattenOptionalFloat(chart.Min),
+		"max":           flattenOptionalFloat(chart.Max),
+		"label":         chart.Label,
+		"related_space": chart.RelatedSpace,
+		"stream":        resourceAppOpticsSpaceChartStreamsGather(d, chart.Streams),
+	}
+
+	layout := spaceChartLayout{}
+	if chart.Layout != nil {
+		layout = *chart.Layout
+	}
+	for k, v := range map[string]int{
+		"row":    layout.Row,
+		"column": layout.Col,
+		"width":  layout.SizeX,
+		"height": layout.SizeY,
+	} {
+		if _, ok := d.GetOk(prefix + k); ok {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// Expands the stream blocks of a chart into their API representation. isSet
+// paths are relative to the chart.
+func resourceAppOpticsSpaceChartStreamsExpand(in []interface{}, isSet attributeSetFunc) []spaceChartStream {
+	streams := make([]spaceChartStream, len(in))
+	for i, streamDataM := range in {
+		streamData := streamDataM.(map[string]interface{})
+		var stream spaceChartStream
+		if v, ok := streamData["metric"].(string); ok && v != "" {
+			stream.Metric = v
+		}
+		if v, ok := streamData["tags"].([]interface{}); ok {
+			stream.Tags = expandStreamTags(v)
+		}
+		if v, ok := streamData["composite"].(string); ok && v != "" {
+			stream.Composite = v
+		}
+		if v, ok := streamData["group_function"].(string); ok && v != "" {
+			stream.GroupFunction = v
+		}
+		if v, ok := streamData["summary_function"].(string); ok && v != "" {
+			stream.SummaryFunction = v
+		}
+		if v, ok := streamData["transform_function"].(string); ok && v != "" {
+			stream.TransformFunction = v
+		}
+		if v, ok := streamData["name"].(string); ok && v != "" {
+			stream.Name = v
+		}
+		if v, ok := streamData["color"].(string); ok && v != "" {
+			stream.Color = v
+		}
+		if v, ok := streamData["units_short"].(string); ok && v != "" {
+			stream.UnitsShort = v
+		}
+		if v, ok := streamData["units_long"].(string); ok && v != "" {
+			stream.UnitsLong = v
+		}
+		stream.Min = expandOptionalInt(streamData["min"], fmt.Sprintf("stream.%d.min", i), isSet)
+		stream.Max = expandOptionalInt(streamData["max"], fmt.Sprintf("stream.%d.max", i), isSet)
+		if v, ok := streamData["period"].(int); ok {
+			stream.Period = v
+		}
+		streams[i] = stream
+	}
+
+	return streams
+}
+
+func expandStreamTags(in []interface{}) []appoptics.Tag {
+	tags := make([]appoptics.Tag, 0, len(in))
+	for _, tagDataM := range in {
+		tagData, ok := tagDataM.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tag := appoptics.Tag{}
+		tag.Name, _ = tagData["name"].(string)
+		tag.Grouped, _ = tagData["grouped"].(bool)
+		tag.Dynamic, _ = tagData["dynamic"].(bool)
+		if values, ok := tagData["values"].([]interface{}); ok {
+			for _, v := range values {
+				tag.Values = append(tag.Values, v.(string))
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func resourceAppOpticsSpaceChartStreamsGather(d *schema.ResourceData, streams []spaceChartStream) []map[string]interface{} {
 	retStreams := make([]map[string]interface{}, 0, len(streams))
 	for _, s := range streams {
 		stream := make(map[string]interface{})
@@ -346,6 +778,10 @@ func resourceAppOpticsSpaceChartStreamsGather(d *schema.ResourceData, streams []
 		stream["color"] = s.Color
 		stream["units_short"] = s.UnitsShort
 		stream["units_long"] = s.UnitsLong
+		stream["name"] = s.Name
+		stream["min"] = flattenOptionalInt(s.Min)
+		stream["max"] = flattenOptionalInt(s.Max)
+		stream["period"] = s.Period
 		retStreams = append(retStreams, stream)
 	}
 
@@ -358,6 +794,7 @@ func flattenStreamTags(in []appoptics.Tag) []interface{} {
 		m := make(map[string]interface{})
 		m["name"] = v.Name
 		m["grouped"] = v.Grouped
+		m["dynamic"] = v.Dynamic
 		if len(v.Values) > 0 {
 			m["values"] = flattenStreamTagsValues(v.Values)
 		}
@@ -376,7 +813,7 @@ func flattenStreamTagsValues(in []string) []interface{} {
 }
 
 func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	spaceID := d.Get("space_id").(int)
 	chartID, err := strconv.Atoi(d.Id())
@@ -384,13 +821,19 @@ func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	if d.HasChange("stream") {
+		if err := resourceAppOpticsSpaceChartValidateDynamicTags(client, spaceID, d.Get("stream").([]interface{})); err != nil {
+			return err
+		}
+	}
+
 	// Just to have whole object for comparison before/after update
-	fullChart, err := client.ChartsService().Retrieve(chartID, spaceID)
+	fullChart, err := spaceChartRetrieve(client, chartID, spaceID)
 	if err != nil {
 		return err
 	}
 
-	spaceChart := &appoptics.Chart{}
+	spaceChart := &spaceChartData{}
 	spaceChart.ID = chartID
 	if d.HasChange("name") {
 		spaceChart.Name = d.Get("name").(string)
@@ -400,14 +843,20 @@ func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{})
 		if math.IsNaN(d.Get("min").(float64)) {
 			return fmt.Errorf("Error updating AppOptics chart. 'min' cannot be converted to a float64. %s: %s", d.Get("min"), err)
 		}
-		spaceChart.Min = d.Get("min").(float64)
+		if v, ok := d.GetOkExists("min"); ok {
+			min := v.(float64)
+			spaceChart.Min = &min
+		}
 		fullChart.Min = spaceChart.Min
 	}
 	if d.HasChange("max") {
 		if math.IsNaN(d.Get("max").(float64)) {
 			return fmt.Errorf("Error updating AppOptics chart. 'max' cannot be converted to a float64. %s: %s", d.Get("max"), err)
 		}
-		spaceChart.Max = d.Get("max").(float64)
+		if v, ok := d.GetOkExists("max"); ok {
+			max := v.(float64)
+			spaceChart.Max = &max
+		}
 		fullChart.Max = spaceChart.Max
 	}
 	if d.HasChange("label") {
@@ -419,54 +868,45 @@ func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{})
 		fullChart.RelatedSpace = spaceChart.RelatedSpace
 	}
 	if d.HasChange("stream") {
-		vs := d.Get("stream").(*schema.Set)
-		streams := make([]appoptics.Stream, vs.Len())
-		for i, streamDataM := range vs.List() {
-			streamData := streamDataM.(map[string]interface{})
-			var stream appoptics.Stream
-			if v, ok := streamData["metric"].(string); ok && v != "" {
-				stream.Metric = v
-			}
-			if v, ok := streamData["tags"].([]appoptics.Tag); ok {
-				stream.Tags = v
-			}
-			if v, ok := streamData["composite"].(string); ok && v != "" {
-				stream.Composite = v
-			}
-			if v, ok := streamData["group_function"].(string); ok && v != "" {
-				stream.GroupFunction = v
-			}
-			if v, ok := streamData["summary_function"].(string); ok && v != "" {
-				stream.SummaryFunction = v
-			}
-			if v, ok := streamData["transform_function"].(string); ok && v != "" {
-				stream.TransformFunction = v
-			}
-			if v, ok := streamData["color"].(string); ok && v != "" {
-				stream.Color = v
-			}
-			if v, ok := streamData["units_short"].(string); ok && v != "" {
-				stream.UnitsShort = v
-			}
-			if v, ok := streamData["units_longs"].(string); ok && v != "" {
-				stream.UnitsLong = v
-			}
-			if v, ok := streamData["min"].(int); ok {
-				stream.Min = v
-			}
-			if v, ok := streamData["max"].(int); ok {
-				stream.Max = v
-			}
-			streams[i] = stream
-		}
+		streams := resourceAppOpticsSpaceChartStreamsExpand(d.Get("stream").([]interface{}), resourceDataSetFunc(d, ""))
 		spaceChart.Streams = streams
 		fullChart.Streams = streams
 	}
+	if d.HasChanges("bignumber", "stacked") {
+		// The options go with the extras, the type itself is left alone
+		options := &spaceChartData{Chart: appoptics.Chart{Type: d.Get("type").(string)}}
+		resourceAppOpticsSpaceChartTypeOptionsExpand(options, d.Get("bignumber").([]interface{}), d.Get("stacked").([]interface{}))
+		spaceChart.UseLastValue = options.UseLastValue
+		spaceChart.Thresholds = options.Thresholds
+		spaceChart.Stacking = options.Stacking
+		fullChart.UseLastValue = spaceChart.UseLastValue
+		fullChart.Thresholds = spaceChart.Thresholds
+		fullChart.Stacking = spaceChart.Stacking
+	}
+	if d.HasChanges("row", "column", "width", "height") {
+		spaceChart.Layout = resourceAppOpticsSpaceChartLayoutExpand(d)
+		if spaceChart.Layout != nil {
+			base := spaceChartLayout{}
+			if fullChart.Layout != nil {
+				base = *fullChart.Layout
+			}
+			fullChart.Layout = spaceChart.Layout.mergedOnto(base)
+		}
+	}
 
-	_, err = client.ChartsService().Update(spaceChart, spaceID)
+	cleared := make([]string, 0)
+	if d.HasChange("min") && spaceChart.Min == nil {
+		cleared = append(cleared, "min")
+	}
+	if d.HasChange("max") && spaceChart.Max == nil {
+		cleared = append(cleared, "max")
+	}
+
+	err = spaceChartUpdate(client, spaceChart, spaceID, cleared...)
 	if err != nil {
 		return fmt.Errorf("Error updating AppOptics chart %s: %s", spaceChart.Name, err)
 	}
+	meta.(*providerMeta).charts.invalidate(spaceID)
 
 	// Wait for propagation since AppOptics updates are eventually consistent
 	wait := resource.StateChangeConf{
@@ -477,7 +917,7 @@ func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{})
 		ContinuousTargetOccurence: 5,
 		Refresh: func() (interface{}, string, error) {
 			log.Printf("[DEBUG] Checking if AppOptics chart %d was updated yet", chartID)
-			changedChart, getErr := client.ChartsService().Retrieve(chartID, spaceID)
+			changedChart, getErr := spaceChartRetrieve(client, chartID, spaceID)
 			if getErr != nil {
 				return changedChart, "", getErr
 			}
@@ -496,7 +936,7 @@ func resourceAppOpticsSpaceChartUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceAppOpticsSpaceChartDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*appoptics.Client)
+	client := meta.(*providerMeta).client
 
 	spaceID := d.Get("space_id").(int)
 
@@ -505,11 +945,16 @@ func resourceAppOpticsSpaceChartDelete(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	if err := checkDeletionProtection(d, "chart", d.Get("name").(string)); err != nil {
+		return err
+	}
+
 	log.Printf("[INFO] Deleting Chart: %d/%d", spaceID, uint(id))
 	err = client.ChartsService().Delete(id, spaceID)
 	if err != nil {
 		return fmt.Errorf("Error deleting chart: %s", err)
 	}
+	meta.(*providerMeta).charts.invalidate(spaceID)
 
 	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := client.ChartsService().Retrieve(id, spaceID)
@@ -529,3 +974,306 @@ func resourceAppOpticsSpaceChartDelete(d *schema.ResourceData, meta interface{})
 	d.SetId("")
 	return nil
 }
+
+// spaceChartData is a chart with the attributes the client library models in
+// appoptics.Chart and the ones it doesn't yet, see spaceChartExtras.
+type spaceChartData struct {
+	appoptics.Chart
+	Layout *spaceChartLayout
+
+	// These shadow the fields of appoptics.Chart, which can't tell zero from
+	// unset
+	Min     *float64
+	Max     *float64
+	Streams []spaceChartStream
+
+	// bignumber options
+	UseLastValue *bool
+	Thresholds   *[]spaceChartThreshold
+
+	// stacked options
+	Stacking *string
+}
+
+// spaceChartExtras are the attributes of a chart the client library doesn't
+// model: its layout, the type specific options and bounds of zero, which
+// appoptics.Chart leaves out. Charts are read and written raw as a whole, see
+// spaceChartResponse and requestBody.
+type spaceChartExtras struct {
+	ID     int               `json:"id,omitempty"`
+	Layout *spaceChartLayout `json:"layout,omitempty"`
+
+	Min     *float64           `json:"min,omitempty"`
+	Max     *float64           `json:"max,omitempty"`
+	Streams []spaceChartStream `json:"streams,omitempty"`
+
+	UseLastValue *bool                  `json:"use_last_value,omitempty"`
+	Thresholds   *[]spaceChartThreshold `json:"thresholds,omitempty"`
+	Stacking     *string                `json:"stacking,omitempty"`
+}
+
+// spaceChartStream is a stream of the charts API. Like spaceChartData, it
+// shadows the bounds of appoptics.Stream so that zero can be sent.
+type spaceChartStream struct {
+	appoptics.Stream
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+}
+
+// spaceChartThreshold colors a bignumber chart when its value crosses Value
+type spaceChartThreshold struct {
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+	Type     string  `json:"type"`
+}
+
+// spaceChartLayout is the position and size of a chart on the dashboard grid
+type spaceChartLayout struct {
+	Row   int `json:"row,omitempty"`
+	Col   int `json:"col,omitempty"`
+	SizeX int `json:"size_x,omitempty"`
+	SizeY int `json:"size_y,omitempty"`
+}
+
+// Returns the layout with its unset values taken from base
+func (l *spaceChartLayout) mergedOnto(base spaceChartLayout) *spaceChartLayout {
+	if l.Row != 0 {
+		base.Row = l.Row
+	}
+	if l.Col != 0 {
+		base.Col = l.Col
+	}
+	if l.SizeX != 0 {
+		base.SizeX = l.SizeX
+	}
+	if l.SizeY != 0 {
+		base.SizeY = l.SizeY
+	}
+	return &base
+}
+
+// Returns the attributes of the chart the client library models
+func (c *spaceChartData) libraryChart() *appoptics.Chart {
+	chart := c.Chart
+	if c.Min != nil {
+		chart.Min = *c.Min
+	}
+	if c.Max != nil {
+		chart.Max = *c.Max
+	}
+	chart.Streams = nil
+	for _, s := range c.Streams {
+		stream := s.Stream
+		if s.Min != nil {
+			stream.Min = *s.Min
+		}
+		if s.Max != nil {
+			stream.Max = *s.Max
+		}
+		chart.Streams = append(chart.Streams, stream)
+	}
+	return &chart
+}
+
+// Returns the attributes of the chart the client library can't send, or nil
+// when there are none
+func (c *spaceChartData) extras() *spaceChartExtras {
+	extras := &spaceChartExtras{
+		Layout:       c.Layout,
+		UseLastValue: c.UseLastValue,
+		Thresholds:   c.Thresholds,
+		Stacking:     c.Stacking,
+	}
+	if c.Min != nil && *c.Min == 0 {
+		extras.Min = c.Min
+	}
+	if c.Max != nil && *c.Max == 0 {
+		extras.Max = c.Max
+	}
+	for _, s := range c.Streams {
+		if (s.Min != nil && *s.Min == 0) || (s.Max != nil && *s.Max == 0) {
+			// Streams can only be replaced as a whole
+			extras.Streams = c.Streams
+			break
+		}
+	}
+
+	if reflect.DeepEqual(*extras, spaceChartExtras{}) {
+		return nil
+	}
+	return extras
+}
+
+// Builds a chart from what the client library read and the extras read raw.
+// Bounds the library read as zero are taken from the extras, which tell zero
+// from unset.
+func newSpaceChartData(chart *appoptics.Chart, extras *spaceChartExtras) *spaceChartData {
+	if extras == nil {
+		extras = &spaceChartExtras{}
+	}
+
+	data := &spaceChartData{
+		Chart:        *chart,
+		Layout:       extras.Layout,
+		Min:          optionalFloatOr(chart.Min, extras.Min),
+		Max:          optionalFloatOr(chart.Max, extras.Max),
+		UseLastValue: extras.UseLastValue,
+		Thresholds:   extras.Thresholds,
+		Stacking:     extras.Stacking,
+	}
+	data.Chart.Min, data.Chart.Max, data.Chart.Streams = 0, 0, nil
+
+	for i, s := range chart.Streams {
+		raw := spaceChartStream{}
+		if i < len(extras.Streams) {
+			raw = extras.Streams[i]
+		}
+		stream := spaceChartStream{
+			Stream: s,
+			Min:    optionalIntOr(s.Min, raw.Min),
+			Max:    optionalIntOr(s.Max, raw.Max),
+		}
+		stream.Stream.Min, stream.Stream.Max = 0, 0
+		data.Streams = append(data.Streams, stream)
+	}
+
+	return data
+}
+
+func optionalFloatOr(v float64, zero *float64) *float64 {
+	if v != 0 {
+		return &v
+	}
+	return zero
+}
+
+func optionalIntOr(v int, zero *int) *int {
+	if v != 0 {
+		return &v
+	}
+	return zero
+}
+
+// Returns the chart as a body of the charts API, with the attributes the
+// client library models and the extras, so that a chart is written in a single
+// request. The bounds named in cleared are sent as null, which removes them.
+func (c *spaceChartData) requestBody(cleared ...string) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if err := mergeJSONObject(body, c.libraryChart()); err != nil {
+		return nil, err
+	}
+	if extras := c.extras(); extras != nil {
+		if err := mergeJSONObject(body, extras); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range cleared {
+		body[key] = nil
+	}
+	return body, nil
+}
+
+// Adds the JSON attributes of v to body, replacing the ones it already has
+func mergeJSONObject(body map[string]interface{}, v interface{}) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, &body)
+}
+
+// Returns the bounds of current that chart no longer has, to be cleared by the
+// update of current to chart
+func spaceChartClearedBounds(current, chart *spaceChartData) []string {
+	cleared := make([]string, 0)
+	if current.Min != nil && chart.Min == nil {
+		cleared = append(cleared, "min")
+	}
+	if current.Max != nil && chart.Max == nil {
+		cleared = append(cleared, "max")
+	}
+	return cleared
+}
+
+func spaceChartCreate(client *appoptics.Client, chart *spaceChartData, spaceID int) (*spaceChartData, error) {
+	body, err := chart.requestBody()
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.NewRequest("POST", fmt.Sprintf("spaces/%d/charts", spaceID), body)
+	if err != nil {
+		return nil, err
+	}
+	created := &spaceChartResponse{}
+	if _, err := client.Do(req, created); err != nil {
+		return nil, err
+	}
+
+	result := *chart
+	result.ID = created.chart.ID
+	return &result, nil
+}
+
+// spaceChartResponse is a chart as the charts API returns it. It's read with a
+// single request and split into what the client library models and the
+// extras.
+type spaceChartResponse struct {
+	chart  appoptics.Chart
+	extras spaceChartExtras
+}
+
+func (r *spaceChartResponse) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &r.chart); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &r.extras)
+}
+
+func spaceChartRetrieve(client *appoptics.Client, chartID, spaceID int) (*spaceChartData, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("spaces/%d/charts/%d", spaceID, chartID), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &spaceChartResponse{}
+	if _, err := client.Do(req, resp); err != nil {
+		return nil, err
+	}
+
+	return newSpaceChartData(&resp.chart, &resp.extras), nil
+}
+
+func spaceChartList(client *appoptics.Client, spaceID int) ([]*spaceChartData, error) {
+	req, err := client.NewRequest("GET", fmt.Sprintf("spaces/%d/charts", spaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp []*spaceChartResponse
+	if _, err := client.Do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make([]*spaceChartData, 0, len(resp))
+	for _, chart := range resp {
+		out = append(out, newSpaceChartData(&chart.chart, &chart.extras))
+	}
+	return out, nil
+}
+
+// Updates the attributes set on chart in a single request, leaving the others
+// as they are. The bounds named in cleared are removed.
+func spaceChartUpdate(client *appoptics.Client, chart *spaceChartData, spaceID int, cleared ...string) error {
+	body, err := chart.requestBody(cleared...)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(body, map[string]interface{}{"id": float64(chart.ID)}) {
+		return nil
+	}
+
+	req, err := client.NewRequest("PUT", fmt.Sprintf("spaces/%d/charts/%d", spaceID, chart.ID), body)
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(req, nil)
+	return err
+}
@@ -0,0 +1,139 @@
+package appoptics
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceAppOpticsSpaceChartResourceV0 is the chart schema before streams
+// became an ordered list. It's a frozen copy without the validation and the
+// set hash, only its shape matters to decode older states.
+func resourceAppOpticsSpaceChartResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"space_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Optional: true,
+			},
+			"min": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"max": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+			},
+			"label": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"related_space": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"stream": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"metric": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"tags": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"grouped": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+									"dynamic": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+									"values": {
+										Type:     schema.TypeList,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"group_function": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"composite": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"summary_function": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"color": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"units_short": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"units_long": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"min": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"max": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"transform_function": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"period": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Streams were a set and are now a list. Both are stored as arrays, so the
+// streams keep the order they had in the state; the next refresh puts them in
+// the order of the API.
+func resourceAppOpticsSpaceChartStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if streams, ok := rawState["stream"].([]interface{}); ok {
+		log.Printf("[DEBUG] Moving %d streams of chart %v to a list", len(streams), rawState["id"])
+	}
+
+	return rawState, nil
+}
@@ -0,0 +1,85 @@
+package appoptics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestResourceAppOpticsSpaceChartStateUpgradeV0(t *testing.T) {
+	if v0 := resourceAppOpticsSpaceChartResourceV0(); v0.Schema["stream"].Type != schema.TypeSet {
+		t.Fatalf("the V0 schema should decode streams as a set")
+	}
+
+	streams := []interface{}{
+		map[string]interface{}{"metric": "system.cpu.utilization", "color": "#ff0000"},
+		map[string]interface{}{"metric": "system.cpu.utilization", "color": "#00ff00"},
+	}
+	rawState := map[string]interface{}{
+		"id":       "1234",
+		"space_id": 42,
+		"stream":   streams,
+	}
+
+	actual, err := resourceAppOpticsSpaceChartStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(actual["stream"], streams) {
+		t.Errorf("unexpected streams:\n%#v", actual["stream"])
+	}
+}
+
+func TestResourceAppOpticsSpaceChartStateUpgradeFromBaseline(t *testing.T) {
+	rawState := testUpgradeFlatmapState(t, resourceAppOpticsSpaceChart(), map[string]string{
+		"id":                                "1234",
+		"space_id":                          "42",
+		"name":                              "CPU",
+		"type":                              "line",
+		"min":                               "0",
+		"max":                               "100",
+		"label":                             "",
+		"related_space":                     "0",
+		"stream.#":                          "2",
+		"stream.411654007.metric":           "system.cpu.utilization",
+		"stream.411654007.group_function":   "average",
+		"stream.411654007.summary_function": "",
+		"stream.411654007.color":            "#ff0000",
+		"stream.411654007.tags.#":           "1",
+		"stream.411654007.tags.0.name":      "hostname",
+		"stream.411654007.tags.0.grouped":   "true",
+		"stream.411654007.tags.0.dynamic":   "false",
+		"stream.411654007.tags.0.values.#":  "1",
+		"stream.411654007.tags.0.values.0":  "*",
+		"stream.411654007.period":           "60",
+		"stream.2736521789.composite":       `s("system.mem.used", "*")`,
+		"stream.2736521789.color":           "#00ff00",
+		"stream.2736521789.tags.#":          "0",
+		"stream.2736521789.period":          "0",
+		"stream.2736521789.min":             "0",
+		"stream.2736521789.max":             "0",
+	})
+
+	streams := rawState["stream"].([]interface{})
+	if len(streams) != 2 {
+		t.Fatalf("unexpected streams: %#v", streams)
+	}
+	byColor := make(map[string]map[string]interface{})
+	for _, v := range streams {
+		stream := v.(map[string]interface{})
+		byColor[stream["color"].(string)] = stream
+	}
+
+	metric := byColor["#ff0000"]
+	if metric["metric"] != "system.cpu.utilization" || metric["group_function"] != "average" {
+		t.Errorf("unexpected metric stream: %#v", metric)
+	}
+	tag := metric["tags"].([]interface{})[0].(map[string]interface{})
+	if tag["name"] != "hostname" || tag["grouped"] != true || !reflect.DeepEqual(tag["values"], []interface{}{"*"}) {
+		t.Errorf("unexpected stream tag: %#v", tag)
+	}
+	if composite := byColor["#00ff00"]["composite"]; composite != `s("system.mem.used", "*")` {
+		t.Errorf("unexpected composite stream: %#v", byColor["#00ff00"])
+	}
+}
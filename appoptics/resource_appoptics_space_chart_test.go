@@ -1,7 +1,9 @@
 package appoptics
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -78,8 +80,150 @@ func TestAccAppOpticsDashboardChart_Updated(t *testing.T) {
 	})
 }
 
+func TestAccAppOpticsDashboardChart_Layout(t *testing.T) {
+	var dashboardChart appoptics.Chart
+	var spaceID int
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardChartDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckAppOpticsDashboardChartConfigLayout,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardChartExists("appoptics_dashboard_chart.foobar", &dashboardChart),
+					testAccCheckAppOpticsDashboardChartSpaceID("appoptics_dashboard_chart.foobar", &spaceID),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "row", "1"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "column", "3"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "width", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "height", "1"),
+				),
+			},
+			// Rearranging the chart outside of Terraform must show up as drift
+			resource.TestStep{
+				PreConfig:          testAccMoveAppOpticsDashboardChart(t, &dashboardChart, &spaceID),
+				Config:             testAccCheckAppOpticsDashboardChartConfigLayout,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAppOpticsDashboardChart_Bignumber(t *testing.T) {
+	var dashboardChart appoptics.Chart
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardChartDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckAppOpticsDashboardChartConfigBignumber,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardChartExists("appoptics_dashboard_chart.foobar", &dashboardChart),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "bignumber.0.use_last_value", "true"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "bignumber.0.threshold.#", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "bignumber.0.threshold.0.color", "red"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAppOpticsDashboardChart_ZeroBounds(t *testing.T) {
+	var dashboardChart appoptics.Chart
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardChartDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckAppOpticsDashboardChartConfigZeroBounds,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardChartExists("appoptics_dashboard_chart.foobar", &dashboardChart),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "min", "0"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_chart.foobar", "stream.0.max", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateSpaceChartTypeOptions(t *testing.T) {
+	options := []interface{}{map[string]interface{}{}}
+
+	cases := []struct {
+		chartType string
+		bignumber []interface{}
+		stacked   []interface{}
+		valid     bool
+	}{
+		{"", nil, nil, true},
+		{"line", nil, nil, true},
+		{"bignumber", options, nil, true},
+		{"stacked", nil, options, true},
+		{"", options, nil, false},
+		{"line", nil, options, false},
+		{"stacked", options, nil, false},
+		{"bignumber", nil, options, false},
+	}
+
+	for _, tc := range cases {
+		err := validateSpaceChartTypeOptions(tc.chartType, tc.bignumber, tc.stacked)
+		if tc.valid && err != nil {
+			t.Errorf("%q chart: unexpected error: %s", tc.chartType, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%q chart: expected an error", tc.chartType)
+		}
+	}
+}
+
+func testAccMoveAppOpticsDashboardChart(t *testing.T, dashboardChart *appoptics.Chart, spaceID *int) func() {
+	return func() {
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		moved := &spaceChartData{
+			Chart:  appoptics.Chart{ID: dashboardChart.ID},
+			Layout: &spaceChartLayout{Row: 2},
+		}
+		if err := spaceChartUpdate(client, moved, *spaceID); err != nil {
+			t.Fatalf("err moving chart: %s", err)
+		}
+	}
+}
+
+func testAccCheckAppOpticsDashboardChartSpaceID(n string, spaceID *int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		id, err := strconv.Atoi(rs.Primary.Attributes["space_id"])
+		if err != nil {
+			return fmt.Errorf("Space ID not a number")
+		}
+		*spaceID = id
+
+		return nil
+	}
+}
+
 func testAccCheckAppOpticsDashboardChartDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*appoptics.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "appoptics_dashboard_chart" {
@@ -117,7 +261,7 @@ func testAccCheckAppOpticsDashboardChartExists(n string, dashboardChart *appopti
 			return fmt.Errorf("No Space Chart ID is set")
 		}
 
-		client := testAccProvider.Meta().(*appoptics.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		id, err := strconv.Atoi(rs.Primary.ID)
 		if err != nil {
@@ -168,6 +312,52 @@ resource "appoptics_dashboard_chart" "foobar" {
 	max = 100
 }`
 
+const testAccCheckAppOpticsDashboardChartConfigLayout = `
+resource "appoptics_dashboard" "foobar" {
+    name = "Foo Bar"
+}
+
+resource "appoptics_dashboard_chart" "foobar" {
+    space_id = "${appoptics_dashboard.foobar.id}"
+    name = "Foo Bar"
+    type = "line"
+    row = 1
+    column = 3
+    width = 2
+    height = 1
+}`
+
+const testAccCheckAppOpticsDashboardChartConfigBignumber = `
+resource "appoptics_dashboard" "foobar" {
+    name = "Foo Bar"
+}
+
+resource "appoptics_dashboard_chart" "foobar" {
+    space_id = "${appoptics_dashboard.foobar.id}"
+    name = "Foo Bar"
+    type = "bignumber"
+
+    bignumber {
+        use_last_value = true
+
+        threshold {
+            operator = ">"
+            value = 90
+            color = "red"
+        }
+
+        threshold {
+            operator = ">"
+            value = 75
+            color = "yellow"
+        }
+    }
+
+    stream {
+        metric = "system.cpu.utilization"
+    }
+}`
+
 const testAccCheckAppOpticsDashboardChartConfigFull = `
 resource "appoptics_dashboard" "foobar" {
     name = "Foo Bar"
@@ -221,3 +411,119 @@ resource "appoptics_dashboard_chart" "foobar" {
 		}
     }
 }`
+
+const testAccCheckAppOpticsDashboardChartConfigZeroBounds = `
+resource "appoptics_dashboard" "foobar" {
+    name = "Foo Bar"
+}
+
+resource "appoptics_dashboard_chart" "foobar" {
+    space_id = "${appoptics_dashboard.foobar.id}"
+    name = "Foo Bar"
+    min = 0
+    max = 100
+
+    stream {
+        metric = "system.load.1"
+        min = -5
+        max = 0
+    }
+}`
+
+func TestSpaceChartDataSplit(t *testing.T) {
+	ceiling := 100.0
+	chart := &spaceChartData{
+		Chart:   appoptics.Chart{ID: 1, Name: "CPU", Type: "line"},
+		Max:     &ceiling,
+		Streams: []spaceChartStream{{Stream: appoptics.Stream{Metric: "cpu"}}},
+	}
+	if extras := chart.extras(); extras != nil {
+		t.Errorf("expected a chart the library models in full to have no extras, got %#v", extras)
+	}
+	if library := chart.libraryChart(); library.Max != 100 || library.Streams[0].Metric != "cpu" {
+		t.Errorf("unexpected library chart: %#v", library)
+	}
+
+	chart.Layout = &spaceChartLayout{Row: 2}
+	extras := chart.extras()
+	if extras == nil || extras.Layout.Row != 2 || extras.Max != nil || extras.Streams != nil {
+		t.Errorf("expected the extras to hold the layout only, got %#v", extras)
+	}
+
+	// Reading the chart back merges both halves
+	read := newSpaceChartData(chart.libraryChart(), extras)
+	if !reflect.DeepEqual(*read, *chart) {
+		t.Errorf("expected %#v, got %#v", *chart, *read)
+	}
+}
+
+func TestSpaceChartResponse(t *testing.T) {
+	body := `{"id":1,"name":"CPU","type":"line","min":0,"max":100,"layout":{"row":2},
+		"streams":[{"id":3,"metric":"cpu","min":0}]}`
+
+	resp := &spaceChartResponse{}
+	if err := json.Unmarshal([]byte(body), resp); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	chart := newSpaceChartData(&resp.chart, &resp.extras)
+
+	if chart.Name != "CPU" || chart.Layout == nil || chart.Layout.Row != 2 {
+		t.Errorf("unexpected chart: %#v", chart)
+	}
+	if chart.Min == nil || *chart.Min != 0 || chart.Max == nil || *chart.Max != 100 {
+		t.Errorf("expected a min of 0 and a max of 100, got %v and %v", chart.Min, chart.Max)
+	}
+	if stream := chart.Streams[0]; stream.ID != 3 || stream.Min == nil || *stream.Min != 0 || stream.Max != nil {
+		t.Errorf("unexpected stream: %#v", stream)
+	}
+}
+
+func TestSpaceChartRequestBody(t *testing.T) {
+	floor := 0.0
+	chart := &spaceChartData{
+		Chart:  appoptics.Chart{ID: 1, Name: "CPU"},
+		Min:    &floor,
+		Layout: &spaceChartLayout{Row: 2},
+	}
+
+	// Both halves go into one request, and a removed max is sent as null
+	body, err := chart.requestBody(spaceChartClearedBounds(&spaceChartData{Max: &floor}, chart)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]interface{}{
+		"id":     float64(1),
+		"name":   "CPU",
+		"min":    float64(0),
+		"max":    nil,
+		"layout": map[string]interface{}{"row": float64(2)},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected %#v, got %#v", expected, body)
+	}
+}
+
+func TestSpaceChartTypeOptionsSentWithExtras(t *testing.T) {
+	chart := &spaceChartData{Chart: appoptics.Chart{ID: 1, Name: "Load", Type: "bignumber"}}
+	resourceAppOpticsSpaceChartTypeOptionsExpand(chart, []interface{}{
+		map[string]interface{}{
+			"use_last_value": true,
+			"threshold": []interface{}{
+				map[string]interface{}{"operator": ">", "value": 90.0, "color": "red"},
+			},
+		},
+	}, nil)
+
+	extras := chart.extras()
+	if extras == nil || extras.UseLastValue == nil || !*extras.UseLastValue || len(*extras.Thresholds) != 1 {
+		t.Fatalf("expected the bignumber options in the extras, got %#v", extras)
+	}
+	if extras.Stacking != nil {
+		t.Errorf("expected no stacked options on a bignumber chart, got %q", *extras.Stacking)
+	}
+
+	read := newSpaceChartData(chart.libraryChart(), extras)
+	if !reflect.DeepEqual(*read, *chart) {
+		t.Errorf("expected %#v, got %#v", *chart, *read)
+	}
+}
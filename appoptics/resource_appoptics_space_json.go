@@ -0,0 +1,358 @@
+package appoptics
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAppOpticsSpaceJSON() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAppOpticsSpaceJSONCreate,
+		Read:   resourceAppOpticsSpaceJSONRead,
+		Update: resourceAppOpticsSpaceJSONUpdate,
+		Delete: resourceAppOpticsSpaceDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAppOpticsSpaceImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"json": {
+				Type:             schema.TypeString,
+				Required:         true,
+				StateFunc:        normalizeJSON,
+				ValidateFunc:     validation.ValidateJsonString,
+				DiffSuppressFunc: suppressSpaceJSONIDs,
+			},
+			"chart_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+}
+
+// spaceJSONDocument is a space with all of its charts, in display order
+type spaceJSONDocument struct {
+	Name   string                   `json:"name"`
+	Charts []map[string]interface{} `json:"charts"`
+
+	// The IDs the charts had in the document, 0 for charts without one
+	ChartIDs []int `json:"-"`
+}
+
+// Decodes the document and moves the chart IDs out of the charts, since they
+// only pick the chart to update
+func resourceAppOpticsSpaceJSONExpand(rawDocument string) (*spaceJSONDocument, error) {
+	document := &spaceJSONDocument{}
+	if err := json.Unmarshal([]byte(rawDocument), document); err != nil {
+		return nil, fmt.Errorf("Error decoding JSON: %s", err)
+	}
+	if document.Name == "" {
+		return nil, fmt.Errorf("the dashboard JSON needs a name")
+	}
+	document.ChartIDs = make([]int, len(document.Charts))
+	for i, chart := range document.Charts {
+		if chartID, ok := chart["id"].(float64); ok {
+			document.ChartIDs[i] = int(chartID)
+		}
+		delete(chart, "id")
+	}
+
+	return document, nil
+}
+
+// IDs written in the document are neither read back nor compared, so they
+// don't show up as drift
+func suppressSpaceJSONIDs(k, old, new string, d *schema.ResourceData) bool {
+	var oldDocument, newDocument interface{}
+	if err := json.Unmarshal([]byte(old), &oldDocument); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newDocument); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(stripJSONIDs(oldDocument), stripJSONIDs(newDocument))
+}
+
+func resourceAppOpticsSpaceJSONCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	document, err := resourceAppOpticsSpaceJSONExpand(d.Get("json").(string))
+	if err != nil {
+		return err
+	}
+	document.Name = meta.(*providerMeta).managedTitle(document.Name)
+
+	space, err := client.SpacesService().Create(document.Name)
+	if err != nil {
+		return fmt.Errorf("Error creating AppOptics space %s: %s", document.Name, err)
+	}
+	d.SetId(strconv.Itoa(space.ID))
+
+	retryErr := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		_, err := client.SpacesService().Retrieve(space.ID)
+		if err != nil {
+			if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+
+	if retryErr != nil {
+		return retryErr
+	}
+
+	err = resourceAppOpticsSpaceJSONReconcileCharts(d, client, space.ID, document, nil)
+	meta.(*providerMeta).charts.invalidate(space.ID)
+	if err != nil {
+		return err
+	}
+
+	return resourceAppOpticsSpaceJSONRead(d, meta)
+}
+
+func resourceAppOpticsSpaceJSONRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	spaceResp, err := client.SpacesService().Retrieve(id)
+	if err != nil {
+		if errResp, ok := err.(*appoptics.ErrorResponse); ok && errResp.Response.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading AppOptics Space %s: %s", d.Id(), err)
+	}
+
+	req, err := client.NewRequest("GET", fmt.Sprintf("spaces/%d/charts", id), nil)
+	if err != nil {
+		return err
+	}
+	var charts []map[string]interface{}
+	if _, err := client.Do(req, &charts); err != nil {
+		return fmt.Errorf("Error reading charts of AppOptics Space %s: %s", d.Id(), err)
+	}
+
+	chartsByID := make(map[int]map[string]interface{}, len(charts))
+	for _, chart := range charts {
+		if chartID, ok := chart["id"].(float64); ok {
+			chartsByID[int(chartID)] = chart
+		}
+	}
+
+	document := map[string]interface{}{
+		"name": meta.(*providerMeta).configuredTitle(spaceResp.Name),
+	}
+	chartIDs := make([]interface{}, 0, len(spaceResp.Charts))
+	orderedCharts := make([]interface{}, 0, len(spaceResp.Charts))
+	for _, ref := range spaceResp.Charts {
+		chart, ok := chartsByID[ref["id"]]
+		if !ok {
+			continue
+		}
+		chartIDs = append(chartIDs, ref["id"])
+		orderedCharts = append(orderedCharts, chart)
+	}
+	document["charts"] = orderedCharts
+
+	// Only the attributes present in the configured document are compared,
+	// everything the API fills in on its own would otherwise show up as drift.
+	var template interface{}
+	if current := d.Get("json").(string); current != "" {
+		if err := json.Unmarshal([]byte(current), &template); err != nil {
+			return fmt.Errorf("Error decoding JSON: %s", err)
+		}
+	}
+
+	byteArray, err := json.Marshal(projectJSON(document, stripJSONIDs(template)))
+	if err != nil {
+		return fmt.Errorf("Error encoding to JSON: %s", err)
+	}
+
+	if err := d.Set("json", string(byteArray)); err != nil {
+		return err
+	}
+	return d.Set("chart_ids", chartIDs)
+}
+
+func resourceAppOpticsSpaceJSONUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*providerMeta).client
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	document, err := resourceAppOpticsSpaceJSONExpand(d.Get("json").(string))
+	if err != nil {
+		return err
+	}
+
+	document.Name = meta.(*providerMeta).managedTitle(document.Name)
+
+	chartIDs := make([]int, 0)
+	for _, chartID := range d.Get("chart_ids").([]interface{}) {
+		chartIDs = append(chartIDs, chartID.(int))
+	}
+
+	err = resourceAppOpticsSpaceJSONReconcileCharts(d, client, id, document, chartIDs)
+	meta.(*providerMeta).charts.invalidate(id)
+	if err != nil {
+		return err
+	}
+
+	return resourceAppOpticsSpaceJSONRead(d, meta)
+}
+
+// Makes the charts of the space match the document. Charts with the ID of an
+// existing chart update it, the others take the remaining charts by position.
+// Missing charts are created and leftovers deleted.
+func resourceAppOpticsSpaceJSONReconcileCharts(d *schema.ResourceData, client *appoptics.Client, spaceID int, document *spaceJSONDocument, chartIDs []int) error {
+	assignedIDs, leftovers := matchSpaceJSONCharts(document.ChartIDs, chartIDs)
+
+	orderedIDs := make([]int, 0, len(document.Charts))
+	for i, chart := range document.Charts {
+		if chartID := assignedIDs[i]; chartID != 0 {
+			req, err := client.NewRequest("PUT", fmt.Sprintf("spaces/%d/charts/%d", spaceID, chartID), chart)
+			if err != nil {
+				return err
+			}
+			if _, err := client.Do(req, nil); err != nil {
+				return fmt.Errorf("Error updating AppOptics chart %d: %s", chartID, err)
+			}
+			orderedIDs = append(orderedIDs, chartID)
+			continue
+		}
+
+		req, err := client.NewRequest("POST", fmt.Sprintf("spaces/%d/charts", spaceID), chart)
+		if err != nil {
+			return err
+		}
+		created := &appoptics.Chart{}
+		if _, err := client.Do(req, created); err != nil {
+			return fmt.Errorf("Error creating AppOptics chart %v: %s", chart["name"], err)
+		}
+		orderedIDs = append(orderedIDs, created.ID)
+	}
+
+	for _, chartID := range leftovers {
+		if err := resourceAppOpticsSpaceDeleteChart(client, spaceID, chartID); err != nil {
+			return err
+		}
+	}
+
+	// The name goes along with the order, which renames the space if needed
+	return spaceUpdate(client, spaceID, &spaceUpdateRequest{
+		Name:   document.Name,
+		Charts: spaceChartRefs(orderedIDs),
+	})
+}
+
+// Matches the charts of a document to the existing charts of the space.
+// Returns the ID of the chart each document chart updates, 0 for new charts,
+// and the IDs of the charts no document chart took.
+func matchSpaceJSONCharts(documentIDs, chartIDs []int) ([]int, []int) {
+	existing := make(map[int]bool, len(chartIDs))
+	for _, chartID := range chartIDs {
+		existing[chartID] = true
+	}
+
+	assigned := make([]int, len(documentIDs))
+	used := make(map[int]bool)
+	for i, chartID := range documentIDs {
+		if existing[chartID] && !used[chartID] {
+			assigned[i] = chartID
+			used[chartID] = true
+		}
+	}
+
+	remaining := make([]int, 0, len(chartIDs))
+	for _, chartID := range chartIDs {
+		if !used[chartID] {
+			remaining = append(remaining, chartID)
+		}
+	}
+	for i := range assigned {
+		if assigned[i] == 0 && len(remaining) > 0 {
+			assigned[i] = remaining[0]
+			remaining = remaining[1:]
+		}
+	}
+
+	return assigned, remaining
+}
+
+// Returns the parts of remote that are also present in template. Array
+// elements beyond the template are returned whole, minus their IDs, so that
+// objects added outside of Terraform show up as drift.
+func projectJSON(remote, template interface{}) interface{} {
+	switch t := template.(type) {
+	case map[string]interface{}:
+		r, ok := remote.(map[string]interface{})
+		if !ok {
+			return remote
+		}
+		out := make(map[string]interface{}, len(t))
+		for k, tv := range t {
+			if rv, ok := r[k]; ok {
+				out[k] = projectJSON(rv, tv)
+			}
+		}
+		return out
+	case []interface{}:
+		r, ok := remote.([]interface{})
+		if !ok {
+			return remote
+		}
+		out := make([]interface{}, len(r))
+		for i, rv := range r {
+			if i < len(t) {
+				out[i] = projectJSON(rv, t[i])
+			} else {
+				out[i] = stripJSONIDs(rv)
+			}
+		}
+		return out
+	case nil:
+		return stripJSONIDs(remote)
+	default:
+		return remote
+	}
+}
+
+func stripJSONIDs(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, mv := range value {
+			if k != "id" {
+				out[k] = stripJSONIDs(mv)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, av := range value {
+			out[i] = stripJSONIDs(av)
+		}
+		return out
+	default:
+		return v
+	}
+}
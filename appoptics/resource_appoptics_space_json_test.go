@@ -0,0 +1,113 @@
+package appoptics
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/appoptics/appoptics-api-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccAppOpticsDashboardJSONBasic(t *testing.T) {
+	var space appoptics.Space
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsDashboardJSONConfig(name, "CPU", "Memory"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard_json.foobar", &space),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_json.foobar", "chart_ids.#", "2"),
+				),
+			},
+			{
+				Config: testAccCheckAppOpticsDashboardJSONConfig(name, "Memory", "CPU"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard_json.foobar", &space),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard_json.foobar", "chart_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:            "appoptics_dashboard_json.foobar",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"json"},
+			},
+		},
+	})
+}
+
+func TestProjectJSON(t *testing.T) {
+	var remote, template, expected interface{}
+	mustUnmarshal := func(s string, v *interface{}) {
+		if err := json.Unmarshal([]byte(s), v); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	mustUnmarshal(`{"name":"a","id":1,"charts":[{"id":2,"name":"b","type":"line","streams":[{"id":3,"metric":"m","period":60}]},{"id":4,"name":"c"}]}`, &remote)
+	mustUnmarshal(`{"name":"a","charts":[{"name":"b","streams":[{"metric":"m"}]}]}`, &template)
+	mustUnmarshal(`{"name":"a","charts":[{"name":"b","streams":[{"metric":"m"}]},{"name":"c"}]}`, &expected)
+
+	if actual := projectJSON(remote, template); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestMatchSpaceJSONCharts(t *testing.T) {
+	// A chart inserted at the top without an ID leaves the others in place
+	assigned, leftovers := matchSpaceJSONCharts([]int{0, 11, 12}, []int{11, 12})
+	if !reflect.DeepEqual(assigned, []int{0, 11, 12}) || len(leftovers) != 0 {
+		t.Errorf("unexpected match: %v, leftovers %v", assigned, leftovers)
+	}
+
+	// Charts without an ID take the remaining charts by position, and an ID
+	// of another space counts as none
+	assigned, leftovers = matchSpaceJSONCharts([]int{12, 99, 0}, []int{11, 12, 13, 14})
+	if !reflect.DeepEqual(assigned, []int{12, 11, 13}) || !reflect.DeepEqual(leftovers, []int{14}) {
+		t.Errorf("unexpected match: %v, leftovers %v", assigned, leftovers)
+	}
+}
+
+func TestSuppressSpaceJSONIDs(t *testing.T) {
+	old := `{"name":"a","charts":[{"name":"b","streams":[{"metric":"m"}]}]}`
+	written := `{"name":"a","charts":[{"id":2,"name":"b","streams":[{"id":3,"metric":"m"}]}]}`
+	if !suppressSpaceJSONIDs("json", old, written, nil) {
+		t.Error("expected IDs written in the document to be ignored")
+	}
+
+	changed := `{"name":"a","charts":[{"id":2,"name":"c","streams":[{"id":3,"metric":"m"}]}]}`
+	if suppressSpaceJSONIDs("json", old, changed, nil) {
+		t.Error("expected a changed name to show up")
+	}
+}
+
+func testAccCheckAppOpticsDashboardJSONConfig(name, first, second string) string {
+	return fmt.Sprintf(`
+resource "appoptics_dashboard_json" "foobar" {
+  json = jsonencode({
+    name = "%s"
+    charts = [
+      {
+        name    = "%s"
+        type    = "line"
+        streams = [{ metric = "system.cpu.utilization" }]
+      },
+      {
+        name    = "%s"
+        type    = "line"
+        streams = [{ metric = "system.mem.used" }]
+      },
+    ]
+  })
+}`, name, first, second)
+}
@@ -2,12 +2,15 @@ package appoptics
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strconv"
 	"testing"
 
 	"github.com/appoptics/appoptics-api-go"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -32,11 +35,309 @@ func TestAccAppOpticsDashboardBasic(t *testing.T) {
 	})
 }
 
+func TestAccAppOpticsDashboardInlineCharts(t *testing.T) {
+	var space appoptics.Space
+	var spaceID string
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsDashboardConfigInlineCharts(name, "First", "Second", "line"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard.foobar", &space),
+					testAccCheckAppOpticsDashboardID("appoptics_dashboard.foobar", &spaceID),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.#", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.0.name", "First"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.1.name", "Second"),
+				),
+			},
+			{
+				Config: testAccCheckAppOpticsDashboardConfigInlineCharts(name, "Second", "First", "line"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard.foobar", &space),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.#", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.0.name", "Second"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.1.name", "First"),
+				),
+			},
+			{
+				// Changing the type of one chart recreates that chart only,
+				// not the dashboard
+				Config: testAccCheckAppOpticsDashboardConfigInlineCharts(name, "Second", "First", "stacked"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(
+						"appoptics_dashboard.foobar", "id", &spaceID),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.#", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "chart.1.type", "stacked"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAppOpticsSpaceChartTypeForceNew(t *testing.T) {
+	inline := resourceAppOpticsSpace().Schema["chart"].Elem.(*schema.Resource).Schema
+	if inline["type"].ForceNew {
+		t.Error("changing the type of an inline chart shouldn't replace the dashboard")
+	}
+	if !resourceAppOpticsSpaceChart().Schema["type"].ForceNew {
+		t.Error("changing the type of a standalone chart should replace it")
+	}
+}
+
+func TestValidateSpaceChartStreams(t *testing.T) {
+	valid := []interface{}{
+		map[string]interface{}{"metric": "system.cpu.utilization", "group_function": "average"},
+		map[string]interface{}{"composite": `s("system.cpu.utilization", "*")`},
+	}
+	if err := validateSpaceChartStreams(valid); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	conflicting := []interface{}{
+		map[string]interface{}{"composite": `s("system.cpu.utilization", "*")`, "metric": "system.cpu.utilization"},
+	}
+	if err := validateSpaceChartStreams(conflicting); err == nil {
+		t.Error("expected an error for a composite stream with a metric")
+	}
+
+	tagged := []interface{}{
+		map[string]interface{}{
+			"composite": `s("system.cpu.utilization", "*")`,
+			"tags":      []interface{}{map[string]interface{}{"name": "environment"}},
+		},
+	}
+	if err := validateSpaceChartStreams(tagged); err == nil {
+		t.Error("expected an error for a composite stream with tags")
+	}
+}
+
+func TestAccAppOpticsDashboardCopy(t *testing.T) {
+	var space appoptics.Space
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsDashboardConfigCopy(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard.copy", &space),
+					testAccCheckAppOpticsDashboardChartCount("appoptics_dashboard.copy", 1),
+				),
+			},
+		},
+	})
+}
+
+func TestApplySpaceChartSubstitutions(t *testing.T) {
+	chart := &spaceChartData{
+		Chart: appoptics.Chart{Name: "CPU"},
+		Streams: []spaceChartStream{
+			{Stream: appoptics.Stream{
+				Metric: "staging.cpu",
+				Tags: []appoptics.Tag{
+					{Name: "environment", Values: []string{"staging", "shared"}},
+				},
+			}},
+			{Stream: appoptics.Stream{
+				Composite: `s("staging.cpu", "*")`,
+			}},
+		},
+	}
+
+	applySpaceChartSubstitutions(chart,
+		[]interface{}{map[string]interface{}{"from": "staging.", "to": "production."}},
+		[]interface{}{map[string]interface{}{"name": "environment", "from": "staging", "to": "production"}},
+	)
+
+	first := chart.Streams[0]
+	if first.Metric != "production.cpu" {
+		t.Errorf("unexpected metric: %s", first.Metric)
+	}
+	values := first.Tags[0].Values
+	if values[0] != "production" || values[1] != "shared" {
+		t.Errorf("unexpected tag values: %v", values)
+	}
+	if composite := chart.Streams[1].Composite; composite != `s("production.cpu", "*")` {
+		t.Errorf("unexpected composite: %s", composite)
+	}
+}
+
+func TestMatchSpaceChartsByPosition(t *testing.T) {
+	existing := []*spaceChartData{
+		{Chart: appoptics.Chart{ID: 1, Name: "CPU"}},
+		{Chart: appoptics.Chart{ID: 2, Name: "Memory"}},
+		{Chart: appoptics.Chart{ID: 3, Name: "Disk"}},
+	}
+
+	// Renamed blocks keep the chart of their position, the last chart is left
+	matched, leftovers := matchSpaceChartsByPosition(existing, 2)
+	if len(matched) != 2 || matched[0].ID != 1 || matched[1].ID != 2 {
+		t.Errorf("unexpected matches: %v", matched)
+	}
+	if len(leftovers) != 1 || leftovers[0].ID != 3 {
+		t.Errorf("unexpected leftovers: %v", leftovers)
+	}
+
+	matched, leftovers = matchSpaceChartsByPosition(existing, 4)
+	if len(matched) != 4 || matched[2].ID != 3 || matched[3] != nil {
+		t.Errorf("unexpected matches: %v", matched)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("unexpected leftovers: %v", leftovers)
+	}
+}
+
+func TestMatchSpaceChartsByName(t *testing.T) {
+	live := []*spaceChartData{
+		{Chart: appoptics.Chart{ID: 2, Name: "CPU"}},
+		{Chart: appoptics.Chart{ID: 1, Name: "CPU"}},
+		{Chart: appoptics.Chart{ID: 3, Name: "Memory"}},
+	}
+
+	matched, leftovers := matchSpaceChartsByName(live, []string{"CPU", "CPU", "Disk"})
+
+	ids := make([]int, 0, len(matched))
+	for _, chart := range matched {
+		if chart == nil {
+			ids = append(ids, 0)
+		} else {
+			ids = append(ids, chart.ID)
+		}
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 0}) {
+		t.Errorf("unexpected chart IDs: %v", ids)
+	}
+	if len(leftovers) != 1 || leftovers[0].ID != 3 {
+		t.Errorf("unexpected leftovers: %v", leftovers)
+	}
+}
+
+func TestAccAppOpticsDashboardTags(t *testing.T) {
+	var space appoptics.Space
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsDashboardConfigTags(name, "environment"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard.foobar", &space),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "tag.#", "2"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "tag.0.name", "environment"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.foobar", "tag.0.default_values.0", "production"),
+				),
+			},
+			{
+				Config:      testAccCheckAppOpticsDashboardConfigTags(name, "region"),
+				ExpectError: regexp.MustCompile(`"region" is dynamic but the dashboard doesn't declare it`),
+			},
+		},
+	})
+}
+
+func TestAccAppOpticsDashboardChartsLinkingHere(t *testing.T) {
+	var space appoptics.Space
+	name := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAppOpticsDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckAppOpticsDashboardConfigLinking(name),
+			},
+			// The chart only exists once the first step has been applied
+			{
+				Config: testAccCheckAppOpticsDashboardConfigLinking(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAppOpticsDashboardExists("appoptics_dashboard.target", &space),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.target", "charts_linking_here.#", "1"),
+					resource.TestCheckResourceAttr(
+						"appoptics_dashboard.target", "charts_linking_here.0.chart_name", "Drill down"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateSpaceChartDynamicTags(t *testing.T) {
+	streams := []interface{}{
+		map[string]interface{}{
+			"metric": "system.cpu.utilization",
+			"tags": []interface{}{
+				map[string]interface{}{"name": "environment", "dynamic": true},
+				map[string]interface{}{"name": "region", "dynamic": false},
+			},
+		},
+	}
+
+	if err := validateSpaceChartDynamicTags(streams, map[string]bool{}); err == nil {
+		t.Error("expected an error for a dynamic tag on a dashboard without tag variables")
+	}
+	if err := validateSpaceChartDynamicTags(streams, map[string]bool{"environment": true}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := validateSpaceChartDynamicTags(streams, map[string]bool{"host": true}); err == nil {
+		t.Error("expected an error for an undeclared dynamic tag")
+	}
+}
+
+func testAccCheckAppOpticsDashboardChartCount(n string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		id, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("ID not a number")
+		}
+
+		foundSpace, err := client.SpacesService().Retrieve(id)
+		if err != nil {
+			return err
+		}
+
+		if len(foundSpace.Charts) != count {
+			return fmt.Errorf("Expected %d charts, found %d", count, len(foundSpace.Charts))
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckAppOpticsDashboardDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*appoptics.Client)
+	client := testAccProvider.Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "appoptics_dashboard" {
+		if rs.Type != "appoptics_dashboard" && rs.Type != "appoptics_dashboard_json" {
 			continue
 		}
 
@@ -54,6 +355,18 @@ func testAccCheckAppOpticsDashboardDestroy(s *terraform.State) error {
 	return nil
 }
 
+// Records the ID of a dashboard, to make sure a later step didn't replace it
+func testAccCheckAppOpticsDashboardID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
 func testAccCheckAppOpticsDashboardExists(n string, space *appoptics.Space) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -66,7 +379,7 @@ func testAccCheckAppOpticsDashboardExists(n string, space *appoptics.Space) reso
 			return fmt.Errorf("No Space ID is set")
 		}
 
-		client := testAccProvider.Meta().(*appoptics.Client)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		id, err := strconv.Atoi(rs.Primary.ID)
 		if err != nil {
@@ -93,3 +406,120 @@ resource "appoptics_dashboard" "foobar" {
     name = "%s"
 }`, name)
 }
+
+func testAccCheckAppOpticsDashboardConfigInlineCharts(name, first, second, secondType string) string {
+	return fmt.Sprintf(`
+resource "appoptics_dashboard" "foobar" {
+    name = "%s"
+
+    chart {
+        name = "%s"
+        type = "line"
+
+        stream {
+            metric = "system.cpu.utilization"
+        }
+    }
+
+    chart {
+        name = "%s"
+        type = "%s"
+
+        stream {
+            metric = "system.mem.used"
+        }
+    }
+}`, name, first, second, secondType)
+}
+
+func testAccCheckAppOpticsDashboardConfigCopy(name string) string {
+	return fmt.Sprintf(`
+resource "appoptics_dashboard" "template" {
+    name = "%s-template"
+
+    chart {
+        name = "CPU"
+
+        stream {
+            metric = "system.cpu.utilization"
+
+            tags {
+                name = "environment"
+                values = ["staging"]
+            }
+        }
+    }
+}
+
+resource "appoptics_dashboard" "copy" {
+    name = "%s-copy"
+    source_space_id = appoptics_dashboard.template.id
+
+    tag_substitution {
+        name = "environment"
+        from = "staging"
+        to = "production"
+    }
+}`, name, name)
+}
+
+func testAccCheckAppOpticsDashboardConfigTags(name, dynamicTag string) string {
+	return fmt.Sprintf(`
+resource "appoptics_dashboard" "foobar" {
+    name = "%s"
+
+    tag {
+        name = "environment"
+        default_values = ["production"]
+    }
+
+    tag {
+        name = "host"
+    }
+
+    chart {
+        name = "CPU"
+
+        stream {
+            metric = "system.cpu.utilization"
+
+            tags {
+                name = "%s"
+                dynamic = true
+            }
+        }
+    }
+}`, name, dynamicTag)
+}
+
+func testAccCheckAppOpticsDashboardConfigLinking(name string) string {
+	return fmt.Sprintf(`
+resource "appoptics_dashboard" "target" {
+    name = "%s-target"
+}
+
+resource "appoptics_dashboard" "source" {
+    name = "%s-source"
+
+    chart {
+        name = "Drill down"
+        related_space = appoptics_dashboard.target.id
+
+        stream {
+            metric = "system.cpu.utilization"
+        }
+    }
+}`, name, name)
+}
+
+func TestResourceAppOpticsSpaceConfiguredName(t *testing.T) {
+	dashboard := schema.TestResourceDataRaw(t, resourceAppOpticsSpaceSchema(), map[string]interface{}{"name": "Overview"})
+	if name := resourceAppOpticsSpaceConfiguredName(dashboard); name != "Overview" {
+		t.Errorf("unexpected dashboard name: %s", name)
+	}
+
+	document := schema.TestResourceDataRaw(t, resourceAppOpticsSpaceJSON().Schema, map[string]interface{}{"json": `{"name":"Overview","charts":[]}`})
+	if name := resourceAppOpticsSpaceConfiguredName(document); name != "Overview" {
+		t.Errorf("unexpected dashboard JSON name: %s", name)
+	}
+}